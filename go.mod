@@ -32,4 +32,5 @@ require (
 	k8s.io/apiextensions-apiserver v0.18.0
 	k8s.io/apimachinery v0.21.0
 	k8s.io/client-go v0.21.0
+	sigs.k8s.io/yaml v1.2.0
 )
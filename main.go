@@ -2,7 +2,9 @@ package main
 
 import (
 	"context"
+	"errors"
 	"flag"
+	"fmt"
 	"os"
 
 	"github.com/hashicorp/go-hclog"
@@ -28,6 +30,18 @@ func main() {
 		Output:     os.Stderr,
 	})
 
+	if flag.Arg(0) == "generate" {
+		err := provider.Generate(ctx, logger, flag.Args()[1:], os.Stdout)
+		if errors.Is(err, flag.ErrHelp) {
+			return
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	if *debug {
 		provider.ServeReattach(ctx, logger)
 	} else {
@@ -95,6 +95,18 @@ func (s *RawProviderServer) ApplyResourceChange(ctx context.Context, req *tfprot
 			})
 			return resp, nil
 		}
+		if !obj.IsKnown() {
+			// the provider configuration was not known during planning, so the object is worked out now
+			obj, err = s.objectFromManifest(ctx, plannedStateVal["manifest"])
+			if err != nil {
+				resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
+					Severity: tfprotov5.DiagnosticSeverityError,
+					Summary:  "Failed to determine resource object from manifest",
+					Detail:   err.Error(),
+				})
+				return resp, nil
+			}
+		}
 
 		gvk, err := GVKFromTftypesObject(&obj, m)
 		if err != nil {
@@ -156,12 +168,52 @@ func (s *RawProviderServer) ApplyResourceChange(ctx context.Context, req *tfprot
 			return resp, nil
 		}
 
+		fieldManager, forceConflicts, err := s.fieldManager(plannedStateVal["field_manager"])
+		if err != nil {
+			resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
+				Severity:  tfprotov5.DiagnosticSeverityError,
+				Summary:   "Invalid field_manager block",
+				Detail:    err.Error(),
+				Attribute: tftypes.NewAttributePath().WithAttributeName("field_manager"),
+			})
+			return resp, nil
+		}
+
+		op := "update"
+		if applyPriorState.IsNull() {
+			op = "create"
+		}
+		timeout, err := s.timeout(plannedStateVal["timeouts"], op)
+		if err != nil {
+			resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
+				Severity:  tfprotov5.DiagnosticSeverityError,
+				Summary:   "Invalid timeouts block",
+				Detail:    err.Error(),
+				Attribute: tftypes.NewAttributePath().WithAttributeName("timeouts"),
+			})
+			return resp, nil
+		}
+		// the deadline covers both the PATCH request and waiting for the conditions in 'wait_for'
+		applyCtx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+
 		// Call the Kubernetes API to create the new resource
-		result, err := rs.Patch(ctx, rname, types.ApplyPatchType, jsonManifest, metav1.PatchOptions{FieldManager: "Terraform"})
+		result, err := rs.Patch(applyCtx, rname, types.ApplyPatchType, jsonManifest, metav1.PatchOptions{
+			FieldManager: fieldManager,
+			Force:        &forceConflicts,
+		})
 		if err != nil {
 			s.logger.Error("[ApplyResourceChange][Apply]", "API error", spew.Sdump(err), "API response", spew.Sdump(result))
+			if applyCtx.Err() == context.DeadlineExceeded {
+				resp.Diagnostics = append(resp.Diagnostics, timeoutDiagnostic(fmt.Sprintf(`Timed out applying resource "%s"`, rnn), op, timeout, err))
+				return resp, nil
+			}
 			if status := apierrors.APIStatus(nil); errors.As(err, &status) {
-				resp.Diagnostics = append(resp.Diagnostics, APIStatusErrorToDiagnostics(status.Status())...)
+				if cdiags := FieldManagerConflictDiagnostics(status.Status(), fieldManager); len(cdiags) > 0 {
+					resp.Diagnostics = append(resp.Diagnostics, cdiags...)
+				} else {
+					resp.Diagnostics = append(resp.Diagnostics, APIStatusErrorToDiagnostics(status.Status())...)
+				}
 			} else {
 				resp.Diagnostics = append(resp.Diagnostics,
 					&tfprotov5.Diagnostic{
@@ -173,7 +225,9 @@ func (s *RawProviderServer) ApplyResourceChange(ctx context.Context, req *tfprot
 			return resp, nil
 		}
 
-		newResObject, err := payload.ToTFValue(RemoveServerSideFields(result.Object), tsch, tftypes.NewAttributePath())
+		ro := RemoveServerSideFields(result.Object)
+		s.removeIgnoredMetadata(ro, plannedStateVal["manifest"])
+		newResObject, err := payload.ToTFValue(ro, tsch, tftypes.NewAttributePath())
 		if err != nil {
 			return resp, err
 		}
@@ -184,14 +238,6 @@ func (s *RawProviderServer) ApplyResourceChange(ctx context.Context, req *tfprot
 			return resp, fmt.Errorf("failed to determine resource type ID: %s", err)
 		}
 
-		wf, ok := plannedStateVal["wait_for"]
-		if ok {
-			err = s.waitForCompletion(ctx, wf, rs, rname, wt)
-			if err != nil {
-				return resp, err
-			}
-		}
-
 		compObj, err := morph.DeepUnknown(tsch, newResObject, tftypes.NewAttributePath())
 		if err != nil {
 			return resp, err
@@ -208,6 +254,33 @@ func (s *RawProviderServer) ApplyResourceChange(ctx context.Context, req *tfprot
 
 		resp.NewState = &newResState
 
+		wf, ok := plannedStateVal["wait_for"]
+		if ok {
+			wc, err := s.getWatchClient()
+			if err != nil {
+				resp.Diagnostics = append(resp.Diagnostics,
+					&tfprotov5.Diagnostic{
+						Severity: tfprotov5.DiagnosticSeverityError,
+						Summary:  "Failed to retrieve Kubernetes watch client during apply",
+						Detail:   err.Error(),
+					})
+				return resp, nil
+			}
+			var ws dynamic.ResourceInterface = wc.Resource(gvr)
+			if ns {
+				ws = wc.Resource(gvr).Namespace(rnamespace)
+			}
+			err = s.waitForCompletion(applyCtx, wf, rs, ws, rname, wt)
+			if werr := (*WaitTimeoutError)(nil); errors.As(err, &werr) {
+				// the resource was applied, so it is kept in state along with the error
+				resp.Diagnostics = append(resp.Diagnostics, timeoutDiagnostic(fmt.Sprintf(`Timed out waiting on resource "%s"`, rnn), op, timeout, err))
+				return resp, nil
+			}
+			if err != nil {
+				return resp, err
+			}
+		}
+
 	case applyPlannedState.IsNull():
 		// Delete the resource
 		priorStateVal := make(map[string]tftypes.Value)
@@ -258,9 +331,27 @@ func (s *RawProviderServer) ApplyResourceChange(ctx context.Context, req *tfprot
 		} else {
 			rs = c.Resource(gvr)
 		}
-		err = rs.Delete(ctx, rname, metav1.DeleteOptions{})
+		rn := types.NamespacedName{Namespace: rnamespace, Name: rname}.String()
+
+		timeout, err := s.timeout(priorStateVal["timeouts"], "delete")
 		if err != nil {
-			rn := types.NamespacedName{Namespace: rnamespace, Name: rname}.String()
+			resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
+				Severity:  tfprotov5.DiagnosticSeverityError,
+				Summary:   "Invalid timeouts block",
+				Detail:    err.Error(),
+				Attribute: tftypes.NewAttributePath().WithAttributeName("timeouts"),
+			})
+			return resp, nil
+		}
+		deleteCtx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+
+		err = rs.Delete(deleteCtx, rname, metav1.DeleteOptions{})
+		if err != nil {
+			if deleteCtx.Err() == context.DeadlineExceeded {
+				resp.Diagnostics = append(resp.Diagnostics, timeoutDiagnostic(fmt.Sprintf(`Timed out deleting resource "%s"`, rn), "delete", timeout, err))
+				return resp, nil
+			}
 			resp.Diagnostics = append(resp.Diagnostics,
 				&tfprotov5.Diagnostic{
 					Severity: tfprotov5.DiagnosticSeverityError,
@@ -270,6 +361,22 @@ func (s *RawProviderServer) ApplyResourceChange(ctx context.Context, req *tfprot
 			return resp, nil
 		}
 
+		// the object stays around until its finalizers are done, the delete timeout covers that too
+		err = waitForDeletion(deleteCtx, rs, rname, s.waitPollInterval)
+		if err != nil {
+			if deleteCtx.Err() == context.DeadlineExceeded {
+				resp.Diagnostics = append(resp.Diagnostics, timeoutDiagnostic(fmt.Sprintf(`Timed out deleting resource "%s"`, rn), "delete", timeout, err))
+				return resp, nil
+			}
+			resp.Diagnostics = append(resp.Diagnostics,
+				&tfprotov5.Diagnostic{
+					Severity: tfprotov5.DiagnosticSeverityError,
+					Detail:   err.Error(),
+					Summary:  fmt.Sprintf("Failed to wait for deletion of resource %s", rn),
+				})
+			return resp, nil
+		}
+
 		resp.NewState = req.PlannedState
 	}
 	// force a refresh of the OpenAPI foundry on next use
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/davecgh/go-spew/spew"
+	"github.com/hashicorp/go-hclog"
 	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/logging"
 	"github.com/hashicorp/terraform-provider-kubernetes-alpha/openapi"
@@ -16,6 +17,7 @@ import (
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/restmapper"
+	"k8s.io/client-go/util/flowcontrol"
 
 	// this is how client-go expects auth plugins to be loaded
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
@@ -26,6 +28,13 @@ const (
 	OAPIFoundry string = "OPENAPIFOUNDRY"
 )
 
+const (
+	// discoveryDefaultBurst is the burst used by the discovery client when none is configured
+	discoveryDefaultBurst int = 100
+	// throttleLogThreshold is how long a request has to be held back by client-side throttling before it is logged
+	throttleLogThreshold = time.Second
+)
+
 // getDynamicClient returns a configured unstructured (dynamic) client instance
 func (ps *RawProviderServer) getDynamicClient() (dynamic.Interface, error) {
 	if ps.dynamicClient != nil {
@@ -34,7 +43,7 @@ func (ps *RawProviderServer) getDynamicClient() (dynamic.Interface, error) {
 	if ps.clientConfig == nil {
 		return nil, fmt.Errorf("cannot create dynamic client: no client config")
 	}
-	dynClient, err := dynamic.NewForConfig(ps.clientConfig)
+	dynClient, err := dynamic.NewForConfig(ps.throttledClientConfig(rest.DefaultBurst))
 	if err != nil {
 		return nil, err
 	}
@@ -42,6 +51,25 @@ func (ps *RawProviderServer) getDynamicClient() (dynamic.Interface, error) {
 	return dynClient, nil
 }
 
+// getWatchClient returns a dynamic client for watching resources. A watch lasts as long as the wait,
+// so 'request_timeout' doesn't apply to it - each watch sets its own timeout instead.
+func (ps *RawProviderServer) getWatchClient() (dynamic.Interface, error) {
+	if ps.watchClient != nil {
+		return ps.watchClient, nil
+	}
+	if ps.clientConfig == nil {
+		return nil, fmt.Errorf("cannot create watch client: no client config")
+	}
+	c := ps.throttledClientConfig(rest.DefaultBurst)
+	c.Timeout = 0
+	watchClient, err := dynamic.NewForConfig(c)
+	if err != nil {
+		return nil, err
+	}
+	ps.watchClient = watchClient
+	return watchClient, nil
+}
+
 // getDiscoveryClient returns a configured discovery client instance.
 func (ps *RawProviderServer) getDiscoveryClient() (discovery.DiscoveryInterface, error) {
 	if ps.discoveryClient != nil {
@@ -50,7 +78,8 @@ func (ps *RawProviderServer) getDiscoveryClient() (discovery.DiscoveryInterface,
 	if ps.clientConfig == nil {
 		return nil, fmt.Errorf("cannot create discovery client: no client config")
 	}
-	discoClient, err := discovery.NewDiscoveryClientForConfig(ps.clientConfig)
+	// discovery is expected to be bursty, so it gets a larger default burst - same as client-go
+	discoClient, err := discovery.NewDiscoveryClientForConfig(ps.throttledClientConfig(discoveryDefaultBurst))
 	if err != nil {
 		return nil, err
 	}
@@ -84,7 +113,7 @@ func (ps *RawProviderServer) getRestClient() (rest.Interface, error) {
 	if ps.clientConfig == nil {
 		return nil, fmt.Errorf("cannot create REST client: no client config")
 	}
-	restClient, err := rest.UnversionedRESTClientFor(ps.clientConfig)
+	restClient, err := rest.UnversionedRESTClientFor(ps.throttledClientConfig(rest.DefaultBurst))
 	if err != nil {
 		return nil, err
 	}
@@ -119,6 +148,40 @@ func (ps *RawProviderServer) getOAPIv2Foundry() (openapi.Foundry, error) {
 	return oapif, nil
 }
 
+// throttledClientConfig returns a copy of the client config with a rate limiter that logs
+// when requests are delayed by client-side throttling. The configured QPS and burst are used,
+// falling back to the client-go defaults.
+func (ps *RawProviderServer) throttledClientConfig(defaultBurst int) *rest.Config {
+	c := rest.CopyConfig(ps.clientConfig)
+	qps := c.QPS
+	if qps == 0 {
+		qps = rest.DefaultQPS
+	}
+	burst := c.Burst
+	if burst == 0 {
+		burst = defaultBurst
+	}
+	c.RateLimiter = &loggingRateLimiter{
+		RateLimiter: flowcontrol.NewTokenBucketRateLimiter(qps, burst),
+		logger:      ps.logger,
+	}
+	return c
+}
+
+type loggingRateLimiter struct {
+	flowcontrol.RateLimiter
+	logger hclog.Logger
+}
+
+func (l *loggingRateLimiter) Wait(ctx context.Context) error {
+	start := time.Now()
+	err := l.RateLimiter.Wait(ctx)
+	if d := time.Since(start); d > throttleLogThreshold {
+		l.logger.Warn("[Throttle]", "Request delayed by client-side throttling, consider raising 'qps' and 'burst'", d.String())
+	}
+	return err
+}
+
 func loggingTransport(rt http.RoundTripper) http.RoundTripper {
 	return &loggingRountTripper{
 		ot: rt,
@@ -140,6 +203,22 @@ func (t *loggingRountTripper) RoundTrip(req *http.Request) (*http.Response, erro
 }
 
 func (ps *RawProviderServer) checkValidCredentials(ctx context.Context) (diags []*tfprotov5.Diagnostic) {
+	if ps.clientConfigUnknown {
+		diags = append(diags, &tfprotov5.Diagnostic{
+			Severity: tfprotov5.DiagnosticSeverityError,
+			Summary:  "Provider configuration is not known",
+			Detail:   "The provider configuration depends on values that are not known until apply, so the API server cannot be reached yet.",
+		})
+		return
+	}
+	if ps.clientConfig == nil {
+		diags = append(diags, &tfprotov5.Diagnostic{
+			Severity: tfprotov5.DiagnosticSeverityError,
+			Summary:  "Provider is not configured",
+			Detail:   "No Kubernetes client configuration could be loaded. Set 'config_path', 'config_paths' or static credentials in the provider block.",
+		})
+		return
+	}
 	rc, err := ps.getRestClient()
 	if err != nil {
 		diags = append(diags, &tfprotov5.Diagnostic{
@@ -170,3 +249,22 @@ func (ps *RawProviderServer) checkValidCredentials(ctx context.Context) (diags [
 	}
 	return
 }
+
+// impersonateUIDHeader is the header used to impersonate the UID of a user
+const impersonateUIDHeader = "Impersonate-Uid"
+
+// impersonateUIDTransport wraps a transport to set the UID of the impersonated user on every request
+func impersonateUIDTransport(uid string, rt http.RoundTripper) http.RoundTripper {
+	return &impersonateUIDRoundTripper{uid: uid, rt: rt}
+}
+
+type impersonateUIDRoundTripper struct {
+	uid string
+	rt  http.RoundTripper
+}
+
+func (t *impersonateUIDRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.Header.Set(impersonateUIDHeader, t.uid)
+	return t.rt.RoundTrip(req)
+}
@@ -2,13 +2,18 @@ package provider
 
 import (
 	"context"
-	"encoding/pem"
 	"errors"
 	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net/http"
 	"net/url"
 	"os"
+	"path/filepath"
+	"regexp"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/davecgh/go-spew/spew"
 	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
@@ -26,6 +31,9 @@ import (
 
 const minTFVersion string = "v0.14.8"
 
+// execClusterExtensionKey is the cluster extension reserved for the configuration of exec credential plugins
+const execClusterExtensionKey string = "client.authentication.k8s.io/exec"
+
 // ConfigureProvider function
 func (s *RawProviderServer) ConfigureProvider(ctx context.Context, req *tfprotov5.ConfigureProviderRequest) (*tfprotov5.ConfigureProviderResponse, error) {
 	response := &tfprotov5.ConfigureProviderResponse{}
@@ -64,6 +72,13 @@ func (s *RawProviderServer) ConfigureProvider(ctx context.Context, req *tfprotov
 		return response, nil
 	}
 
+	// the provider configuration can depend on resources that don't exist yet (e.g. the cluster itself)
+	s.clientConfigUnknown = !cfgVal.IsFullyKnown()
+	if s.clientConfigUnknown {
+		s.logger.Debug("[Configure]", "provider configuration is not fully known", "deferring client configuration to apply")
+		return response, nil
+	}
+
 	overrides := &clientcmd.ConfigOverrides{}
 	loader := &clientcmd.ClientConfigLoadingRules{}
 
@@ -87,18 +102,74 @@ func (s *RawProviderServer) ConfigureProvider(ctx context.Context, req *tfprotov
 		configPath = configPathEnv
 	}
 	if len(configPath) > 0 {
-		configPathAbs, err := homedir.Expand(configPath)
-		if err == nil {
-			_, err = os.Stat(configPathAbs)
+		if err := checkConfigPath(configPath); err != nil {
+			diags = append(diags, &tfprotov5.Diagnostic{
+				Severity: tfprotov5.DiagnosticSeverityInvalid,
+				Summary:  "Invalid attribute in provider configuration",
+				Detail:   "'config_path' refers to an invalid path: " + err.Error(),
+			})
 		}
+		loader.ExplicitPath, _ = homedir.Expand(configPath)
+	}
+
+	// Handle 'config_paths' attribute
+	//
+	var configPaths []string
+	if !providerConfig["config_paths"].IsNull() && providerConfig["config_paths"].IsKnown() {
+		var cps []tftypes.Value
+		err = providerConfig["config_paths"].As(&cps)
 		if err != nil {
+			// invalid attribute - this shouldn't happen, bail out now
+			response.Diagnostics = append(response.Diagnostics, &tfprotov5.Diagnostic{
+				Severity: tfprotov5.DiagnosticSeverityError,
+				Summary:  "Provider configuration: failed to extract 'config_paths' value",
+				Detail:   err.Error(),
+			})
+			return response, nil
+		}
+		for _, cp := range cps {
+			var p string
+			err = cp.As(&p)
+			if err != nil {
+				// invalid attribute - this shouldn't happen, bail out now
+				response.Diagnostics = append(response.Diagnostics, &tfprotov5.Diagnostic{
+					Severity: tfprotov5.DiagnosticSeverityError,
+					Summary:  "Provider configuration: failed to extract element of 'config_paths' value",
+					Detail:   err.Error(),
+				})
+				return response, nil
+			}
+			configPaths = append(configPaths, p)
+		}
+	}
+	// check environment - this overrides any value found in provider configuration.
+	// The value uses the same format as KUBECONFIG: a list of paths separated by the OS path list separator.
+	if configPathsEnv, ok := os.LookupEnv("KUBE_CONFIG_PATHS"); ok && configPathsEnv != "" {
+		configPaths = filepath.SplitList(configPathsEnv)
+	}
+	// client-go ignores all other files when an explicit path is set, so both can't be used together
+	if len(configPath) > 0 && len(configPaths) > 0 {
+		diags = append(diags, &tfprotov5.Diagnostic{
+			Severity: tfprotov5.DiagnosticSeverityInvalid,
+			Summary:  "Invalid attribute in provider configuration",
+			Detail:   "'config_path' and 'config_paths' cannot be set at the same time, including through the KUBE_CONFIG_PATH and KUBE_CONFIG_PATHS environment variables",
+		})
+	}
+	for _, cp := range configPaths {
+		if cp == "" {
+			continue
+		}
+		if err := checkConfigPath(cp); err != nil {
 			diags = append(diags, &tfprotov5.Diagnostic{
 				Severity: tfprotov5.DiagnosticSeverityInvalid,
 				Summary:  "Invalid attribute in provider configuration",
-				Detail:   "'config_path' refers to an invalid path: " + configPathAbs,
+				Detail:   "'config_paths' refers to an invalid path: " + err.Error(),
 			})
 		}
-		loader.ExplicitPath = configPathAbs
+		cpAbs, _ := homedir.Expand(cp)
+		// files are merged the same way kubectl merges the files in KUBECONFIG:
+		// the first file to set a particular value or map key wins
+		loader.Precedence = append(loader.Precedence, cpAbs)
 	}
 
 	// Handle 'client_certificate' attribute
@@ -118,13 +189,46 @@ func (s *RawProviderServer) ConfigureProvider(ctx context.Context, req *tfprotov
 	if clientCrtEnv, ok := os.LookupEnv("KUBE_CLIENT_CERT_DATA"); ok && clientCrtEnv != "" {
 		clientCertificate = clientCrtEnv
 	}
+
+	// Handle 'client_certificate_file' attribute
+	//
+	var clientCertificateFile string
+	if !providerConfig["client_certificate_file"].IsNull() && providerConfig["client_certificate_file"].IsKnown() {
+		err = providerConfig["client_certificate_file"].As(&clientCertificateFile)
+		if err != nil {
+			// invalid attribute type - this shouldn't happen, bail out for now
+			response.Diagnostics = append(response.Diagnostics, &tfprotov5.Diagnostic{
+				Severity: tfprotov5.DiagnosticSeverityError,
+				Summary:  "Provider configuration: failed to extract 'client_certificate_file' value",
+				Detail:   err.Error(),
+			})
+			return response, nil
+		}
+		if !providerConfig["client_certificate"].IsNull() {
+			diags = append(diags, &tfprotov5.Diagnostic{
+				Severity: tfprotov5.DiagnosticSeverityInvalid,
+				Summary:  "Invalid attribute in provider configuration",
+				Detail:   "'client_certificate' and 'client_certificate_file' cannot be set at the same time",
+			})
+		}
+	}
+	// values set directly, including from the environment, take precedence over the file
+	if len(clientCertificate) == 0 && len(clientCertificateFile) > 0 {
+		clientCertificate, err = readProviderConfigFile(clientCertificateFile)
+		if err != nil {
+			diags = append(diags, &tfprotov5.Diagnostic{
+				Severity: tfprotov5.DiagnosticSeverityInvalid,
+				Summary:  "Invalid attribute in provider configuration",
+				Detail:   "'client_certificate_file' cannot be read: " + err.Error(),
+			})
+		}
+	}
 	if len(clientCertificate) > 0 {
-		ccPEM, _ := pem.Decode([]byte(clientCertificate))
-		if ccPEM == nil || ccPEM.Type != "CERTIFICATE" {
+		if err := checkPEMCertificate("client_certificate", clientCertificate); err != nil {
 			diags = append(diags, &tfprotov5.Diagnostic{
 				Severity: tfprotov5.DiagnosticSeverityInvalid,
 				Summary:  "Invalid attribute in provider configuration",
-				Detail:   "'client_certificate' is not a valid PEM encoded certificate",
+				Detail:   err.Error(),
 			})
 		}
 		overrides.AuthInfo.ClientCertificateData = []byte(clientCertificate)
@@ -148,13 +252,46 @@ func (s *RawProviderServer) ConfigureProvider(ctx context.Context, req *tfprotov
 	if clusterCAEnv, ok := os.LookupEnv("KUBE_CLUSTER_CA_CERT_DATA"); ok && clusterCAEnv != "" {
 		clusterCaCertificate = clusterCAEnv
 	}
+
+	// Handle 'cluster_ca_certificate_file' attribute
+	//
+	var clusterCaCertificateFile string
+	if !providerConfig["cluster_ca_certificate_file"].IsNull() && providerConfig["cluster_ca_certificate_file"].IsKnown() {
+		err = providerConfig["cluster_ca_certificate_file"].As(&clusterCaCertificateFile)
+		if err != nil {
+			// invalid attribute type - this shouldn't happen, bail out for now
+			response.Diagnostics = append(response.Diagnostics, &tfprotov5.Diagnostic{
+				Severity: tfprotov5.DiagnosticSeverityError,
+				Summary:  "Provider configuration: failed to extract 'cluster_ca_certificate_file' value",
+				Detail:   err.Error(),
+			})
+			return response, nil
+		}
+		if !providerConfig["cluster_ca_certificate"].IsNull() {
+			diags = append(diags, &tfprotov5.Diagnostic{
+				Severity: tfprotov5.DiagnosticSeverityInvalid,
+				Summary:  "Invalid attribute in provider configuration",
+				Detail:   "'cluster_ca_certificate' and 'cluster_ca_certificate_file' cannot be set at the same time",
+			})
+		}
+	}
+	// values set directly, including from the environment, take precedence over the file
+	if len(clusterCaCertificate) == 0 && len(clusterCaCertificateFile) > 0 {
+		clusterCaCertificate, err = readProviderConfigFile(clusterCaCertificateFile)
+		if err != nil {
+			diags = append(diags, &tfprotov5.Diagnostic{
+				Severity: tfprotov5.DiagnosticSeverityInvalid,
+				Summary:  "Invalid attribute in provider configuration",
+				Detail:   "'cluster_ca_certificate_file' cannot be read: " + err.Error(),
+			})
+		}
+	}
 	if len(clusterCaCertificate) > 0 {
-		ca, _ := pem.Decode([]byte(clusterCaCertificate))
-		if ca == nil || ca.Type != "CERTIFICATE" {
+		if err := checkPEMCertificate("cluster_ca_certificate", clusterCaCertificate); err != nil {
 			diags = append(diags, &tfprotov5.Diagnostic{
 				Severity: tfprotov5.DiagnosticSeverityInvalid,
 				Summary:  "Invalid attribute in provider configuration",
-				Detail:   "'cluster_ca_certificate' is not a valid PEM encoded certificate",
+				Detail:   err.Error(),
 			})
 		}
 		overrides.ClusterInfo.CertificateAuthorityData = []byte(clusterCaCertificate)
@@ -213,23 +350,16 @@ func (s *RawProviderServer) ConfigureProvider(ctx context.Context, req *tfprotov
 		host = hostEnv
 	}
 	if len(host) > 0 {
-		_, err = url.ParseRequestURI(host)
-		if err != nil {
-			diags = append(diags, &tfprotov5.Diagnostic{
-				Severity: tfprotov5.DiagnosticSeverityInvalid,
-				Summary:  "Invalid attribute in provider configuration",
-				Detail:   "'host' is not a valid URL",
-			})
-		}
-		hostURL, _, err := rest.DefaultServerURL(host, "", apimachineryschema.GroupVersion{}, defaultTLS)
-		if err != nil {
+		if err := checkHost(host); err != nil {
 			diags = append(diags, &tfprotov5.Diagnostic{
 				Severity: tfprotov5.DiagnosticSeverityInvalid,
 				Summary:  "Invalid attribute in provider configuration",
-				Detail:   "Invalid value for 'host': " + err.Error(),
+				Detail:   err.Error(),
 			})
+			response.Diagnostics = diags
 			return response, nil
 		}
+		hostURL, _, _ := rest.DefaultServerURL(host, "", apimachineryschema.GroupVersion{}, defaultTLS)
 		// Server has to be the complete address of the kubernetes cluster (scheme://hostname:port), not just the hostname,
 		// because `overrides` are processed too late to be taken into account by `defaultServerUrlFor()`.
 		// This basically replicates what defaultServerUrlFor() does with config but for overrides,
@@ -237,6 +367,68 @@ func (s *RawProviderServer) ConfigureProvider(ctx context.Context, req *tfprotov
 		overrides.ClusterInfo.Server = hostURL.String()
 	}
 
+	// Handle 'tls_server_name' attribute
+	//
+	var tlsServerName string
+	if !providerConfig["tls_server_name"].IsNull() && providerConfig["tls_server_name"].IsKnown() {
+		err = providerConfig["tls_server_name"].As(&tlsServerName)
+		if err != nil {
+			// invalid attribute type - this shouldn't happen, bail out for now
+			response.Diagnostics = append(response.Diagnostics, &tfprotov5.Diagnostic{
+				Severity: tfprotov5.DiagnosticSeverityError,
+				Summary:  "Provider configuration: failed to extract 'tls_server_name' value",
+				Detail:   err.Error(),
+			})
+			return response, nil
+		}
+	}
+	// check environment - this overrides any value found in provider configuration
+	if tlsServerNameEnv, ok := os.LookupEnv("KUBE_TLS_SERVER_NAME"); ok && tlsServerNameEnv != "" {
+		tlsServerName = tlsServerNameEnv
+	}
+	overrides.ClusterInfo.TLSServerName = tlsServerName
+
+	// Handle 'proxy_url' attribute
+	//
+	var proxyURL string
+	if !providerConfig["proxy_url"].IsNull() && providerConfig["proxy_url"].IsKnown() {
+		err = providerConfig["proxy_url"].As(&proxyURL)
+		if err != nil {
+			// invalid attribute type - this shouldn't happen, bail out for now
+			response.Diagnostics = append(response.Diagnostics, &tfprotov5.Diagnostic{
+				Severity: tfprotov5.DiagnosticSeverityError,
+				Summary:  "Provider configuration: failed to extract 'proxy_url' value",
+				Detail:   err.Error(),
+			})
+			return response, nil
+		}
+	}
+	// check environment - this overrides any value found in provider configuration
+	if proxyURLEnv, ok := os.LookupEnv("KUBE_PROXY_URL"); ok && proxyURLEnv != "" {
+		proxyURL = proxyURLEnv
+	}
+	if len(proxyURL) > 0 {
+		pu, err := url.Parse(proxyURL)
+		if err == nil && pu.Host == "" {
+			err = errors.New("missing host")
+		}
+		if err == nil {
+			switch pu.Scheme {
+			case "http", "https", "socks5":
+			default:
+				err = fmt.Errorf("unsupported scheme %q, must be one of http, https or socks5", pu.Scheme)
+			}
+		}
+		if err != nil {
+			diags = append(diags, &tfprotov5.Diagnostic{
+				Severity: tfprotov5.DiagnosticSeverityInvalid,
+				Summary:  "Invalid attribute in provider configuration",
+				Detail:   "'proxy_url' is not a valid proxy URL: " + err.Error(),
+			})
+		}
+		overrides.ClusterInfo.ProxyURL = proxyURL
+	}
+
 	// Handle 'client_key' attribute
 	//
 	var clientKey string
@@ -256,18 +448,301 @@ func (s *RawProviderServer) ConfigureProvider(ctx context.Context, req *tfprotov
 	if clientKeyEnv, ok := os.LookupEnv("KUBE_CLIENT_KEY_DATA"); ok && clientKeyEnv != "" {
 		clientKey = clientKeyEnv
 	}
+
+	// Handle 'client_key_file' attribute
+	//
+	var clientKeyFile string
+	if !providerConfig["client_key_file"].IsNull() && providerConfig["client_key_file"].IsKnown() {
+		err = providerConfig["client_key_file"].As(&clientKeyFile)
+		if err != nil {
+			// invalid attribute type - this shouldn't happen, bail out for now
+			response.Diagnostics = append(response.Diagnostics, &tfprotov5.Diagnostic{
+				Severity: tfprotov5.DiagnosticSeverityError,
+				Summary:  "Provider configuration: failed to extract 'client_key_file' value",
+				Detail:   err.Error(),
+			})
+			return response, nil
+		}
+		if !providerConfig["client_key"].IsNull() {
+			diags = append(diags, &tfprotov5.Diagnostic{
+				Severity: tfprotov5.DiagnosticSeverityInvalid,
+				Summary:  "Invalid attribute in provider configuration",
+				Detail:   "'client_key' and 'client_key_file' cannot be set at the same time",
+			})
+		}
+	}
+	// values set directly, including from the environment, take precedence over the file
+	if len(clientKey) == 0 && len(clientKeyFile) > 0 {
+		clientKey, err = readProviderConfigFile(clientKeyFile)
+		if err != nil {
+			diags = append(diags, &tfprotov5.Diagnostic{
+				Severity: tfprotov5.DiagnosticSeverityInvalid,
+				Summary:  "Invalid attribute in provider configuration",
+				Detail:   "'client_key_file' cannot be read: " + err.Error(),
+			})
+		}
+	}
 	if len(clientKey) > 0 {
-		ck, _ := pem.Decode([]byte(clientKey))
-		if ck == nil || !strings.Contains(ck.Type, "PRIVATE KEY") {
+		if err := checkPEMPrivateKey("client_key", clientKey); err != nil {
 			diags = append(diags, &tfprotov5.Diagnostic{
 				Severity: tfprotov5.DiagnosticSeverityInvalid,
 				Summary:  "Invalid attribute in provider configuration",
-				Detail:   "'client_key' is not a valid PEM encoded private key",
+				Detail:   err.Error(),
 			})
 		}
 		overrides.AuthInfo.ClientKeyData = []byte(clientKey)
 	}
 
+	// Handle 'qps' attribute
+	//
+	var qps float64
+	if !providerConfig["qps"].IsNull() && providerConfig["qps"].IsKnown() {
+		var qpsVal big.Float
+		err = providerConfig["qps"].As(&qpsVal)
+		if err != nil {
+			// invalid attribute type - this shouldn't happen, bail out for now
+			response.Diagnostics = append(response.Diagnostics, &tfprotov5.Diagnostic{
+				Severity: tfprotov5.DiagnosticSeverityError,
+				Summary:  "Provider configuration: failed to extract 'qps' value",
+				Detail:   err.Error(),
+			})
+			return response, nil
+		}
+		qps, _ = qpsVal.Float64()
+	}
+	if qpsEnv, ok := os.LookupEnv("KUBE_QPS"); ok && qpsEnv != "" {
+		qv, err := strconv.ParseFloat(qpsEnv, 32)
+		if err != nil {
+			diags = append(diags, &tfprotov5.Diagnostic{
+				Severity: tfprotov5.DiagnosticSeverityInvalid,
+				Summary:  "Invalid provider configuration",
+				Detail:   "Environment variable KUBE_QPS contains invalid value: " + err.Error(),
+			})
+		} else {
+			qps = qv
+		}
+	}
+	if qps < 0 {
+		diags = append(diags, &tfprotov5.Diagnostic{
+			Severity: tfprotov5.DiagnosticSeverityInvalid,
+			Summary:  "Invalid attribute in provider configuration",
+			Detail:   "'qps' cannot be negative",
+		})
+	}
+
+	// Handle 'burst' attribute
+	//
+	var burst int64
+	if !providerConfig["burst"].IsNull() && providerConfig["burst"].IsKnown() {
+		var burstVal big.Float
+		err = providerConfig["burst"].As(&burstVal)
+		if err != nil {
+			// invalid attribute type - this shouldn't happen, bail out for now
+			response.Diagnostics = append(response.Diagnostics, &tfprotov5.Diagnostic{
+				Severity: tfprotov5.DiagnosticSeverityError,
+				Summary:  "Provider configuration: failed to extract 'burst' value",
+				Detail:   err.Error(),
+			})
+			return response, nil
+		}
+		if !burstVal.IsInt() {
+			diags = append(diags, &tfprotov5.Diagnostic{
+				Severity: tfprotov5.DiagnosticSeverityInvalid,
+				Summary:  "Invalid attribute in provider configuration",
+				Detail:   "'burst' must be a whole number",
+			})
+		}
+		burst, _ = burstVal.Int64()
+	}
+	if burstEnv, ok := os.LookupEnv("KUBE_BURST"); ok && burstEnv != "" {
+		bv, err := strconv.ParseInt(burstEnv, 10, 32)
+		if err != nil {
+			diags = append(diags, &tfprotov5.Diagnostic{
+				Severity: tfprotov5.DiagnosticSeverityInvalid,
+				Summary:  "Invalid provider configuration",
+				Detail:   "Environment variable KUBE_BURST contains invalid value: " + err.Error(),
+			})
+		} else {
+			burst = bv
+		}
+	}
+	if burst < 0 {
+		diags = append(diags, &tfprotov5.Diagnostic{
+			Severity: tfprotov5.DiagnosticSeverityInvalid,
+			Summary:  "Invalid attribute in provider configuration",
+			Detail:   "'burst' cannot be negative",
+		})
+	}
+
+	// Handle 'request_timeout' attribute
+	//
+	var requestTimeout string
+	if !providerConfig["request_timeout"].IsNull() && providerConfig["request_timeout"].IsKnown() {
+		err = providerConfig["request_timeout"].As(&requestTimeout)
+		if err != nil {
+			// invalid attribute type - this shouldn't happen, bail out for now
+			response.Diagnostics = append(response.Diagnostics, &tfprotov5.Diagnostic{
+				Severity: tfprotov5.DiagnosticSeverityError,
+				Summary:  "Provider configuration: failed to extract 'request_timeout' value",
+				Detail:   err.Error(),
+			})
+			return response, nil
+		}
+	}
+	if requestTimeoutEnv, ok := os.LookupEnv("KUBE_REQUEST_TIMEOUT"); ok && requestTimeoutEnv != "" {
+		requestTimeout = requestTimeoutEnv
+	}
+	var timeout time.Duration
+	if len(requestTimeout) > 0 {
+		timeout, err = time.ParseDuration(requestTimeout)
+		if err == nil && timeout < 0 {
+			err = errors.New("cannot be negative")
+		}
+		if err != nil {
+			diags = append(diags, &tfprotov5.Diagnostic{
+				Severity: tfprotov5.DiagnosticSeverityInvalid,
+				Summary:  "Invalid attribute in provider configuration",
+				Detail:   "'request_timeout' is not a valid duration: " + err.Error(),
+			})
+		}
+	}
+
+	// Handle 'wait_poll_interval' attribute
+	//
+	var waitPollInterval string
+	if !providerConfig["wait_poll_interval"].IsNull() && providerConfig["wait_poll_interval"].IsKnown() {
+		err = providerConfig["wait_poll_interval"].As(&waitPollInterval)
+		if err != nil {
+			// invalid attribute type - this shouldn't happen, bail out for now
+			response.Diagnostics = append(response.Diagnostics, &tfprotov5.Diagnostic{
+				Severity: tfprotov5.DiagnosticSeverityError,
+				Summary:  "Provider configuration: failed to extract 'wait_poll_interval' value",
+				Detail:   err.Error(),
+			})
+			return response, nil
+		}
+	}
+	if waitPollIntervalEnv, ok := os.LookupEnv("KUBE_WAIT_POLL_INTERVAL"); ok && waitPollIntervalEnv != "" {
+		waitPollInterval = waitPollIntervalEnv
+	}
+	pollInterval := defaultWaitPollInterval
+	if len(waitPollInterval) > 0 {
+		pollInterval, err = time.ParseDuration(waitPollInterval)
+		if err == nil && pollInterval <= 0 {
+			err = errors.New("must be positive")
+		}
+		if err != nil {
+			diags = append(diags, &tfprotov5.Diagnostic{
+				Severity: tfprotov5.DiagnosticSeverityInvalid,
+				Summary:  "Invalid attribute in provider configuration",
+				Detail:   "'wait_poll_interval' is not a valid duration: " + err.Error(),
+			})
+		}
+	}
+
+	// Handle 'default_namespace' attribute
+	//
+	var defaultNamespace string
+	if !providerConfig["default_namespace"].IsNull() && providerConfig["default_namespace"].IsKnown() {
+		err = providerConfig["default_namespace"].As(&defaultNamespace)
+		if err != nil {
+			// invalid attribute type - this shouldn't happen, bail out for now
+			response.Diagnostics = append(response.Diagnostics, &tfprotov5.Diagnostic{
+				Severity: tfprotov5.DiagnosticSeverityError,
+				Summary:  "Provider configuration: failed to assert type of 'default_namespace' value",
+				Detail:   err.Error(),
+			})
+			return response, nil
+		}
+	}
+	// check environment - this overrides any value found in provider configuration
+	if defaultNamespaceEnv, ok := os.LookupEnv("KUBE_DEFAULT_NAMESPACE"); ok && defaultNamespaceEnv != "" {
+		defaultNamespace = defaultNamespaceEnv
+	}
+	if len(defaultNamespace) > 0 {
+		if err := checkNamespaceName(defaultNamespace); err != nil {
+			diags = append(diags, &tfprotov5.Diagnostic{
+				Severity: tfprotov5.DiagnosticSeverityInvalid,
+				Summary:  "Invalid attribute in provider configuration",
+				Detail:   err.Error(),
+			})
+		}
+	}
+
+	// Handle 'default_labels' and 'default_annotations' attributes
+	//
+	defaultMetadata := make(map[string]map[string]string)
+	for _, name := range []string{"default_labels", "default_annotations"} {
+		m, err := providerConfigStringMap(providerConfig[name])
+		if err != nil {
+			// invalid attribute type - this shouldn't happen, bail out for now
+			response.Diagnostics = append(response.Diagnostics, &tfprotov5.Diagnostic{
+				Severity: tfprotov5.DiagnosticSeverityError,
+				Summary:  fmt.Sprintf("Provider configuration: failed to assert type of '%s' value", name),
+				Detail:   err.Error(),
+			})
+			return response, nil
+		}
+		for _, e := range checkMetadataDefaults(name, m) {
+			diags = append(diags, &tfprotov5.Diagnostic{
+				Severity: tfprotov5.DiagnosticSeverityInvalid,
+				Summary:  "Invalid attribute in provider configuration",
+				Detail:   fmt.Sprintf("'%s' %s", name, e),
+			})
+		}
+		defaultMetadata[name] = m
+	}
+
+	// Handle 'ignore_labels' and 'ignore_annotations' attributes
+	//
+	ignoreMetadata := make(map[string][]*regexp.Regexp)
+	for _, name := range []string{"ignore_labels", "ignore_annotations"} {
+		exprs, err := providerConfigStringList(providerConfig[name])
+		if err != nil {
+			// invalid attribute type - this shouldn't happen, bail out for now
+			response.Diagnostics = append(response.Diagnostics, &tfprotov5.Diagnostic{
+				Severity: tfprotov5.DiagnosticSeverityError,
+				Summary:  fmt.Sprintf("Provider configuration: failed to assert type of '%s' value", name),
+				Detail:   err.Error(),
+			})
+			return response, nil
+		}
+		for _, e := range exprs {
+			re, err := regexp.Compile(e)
+			if err != nil {
+				diags = append(diags, &tfprotov5.Diagnostic{
+					Severity: tfprotov5.DiagnosticSeverityInvalid,
+					Summary:  "Invalid attribute in provider configuration",
+					Detail:   fmt.Sprintf("'%s' contains an invalid regular expression: %s", name, err),
+				})
+				continue
+			}
+			ignoreMetadata[name] = append(ignoreMetadata[name], re)
+		}
+	}
+
+	// Handle 'field_manager' block
+	//
+	fieldManagerDefaults, err := parseFieldManagerBlock(providerConfig["field_manager"])
+	if err != nil {
+		diags = append(diags, &tfprotov5.Diagnostic{
+			Severity: tfprotov5.DiagnosticSeverityInvalid,
+			Summary:  "Invalid attribute in provider configuration",
+			Detail:   "'field_manager' " + err.Error(),
+		})
+	}
+
+	// Handle 'timeouts' block
+	//
+	timeoutDefaults, err := parseTimeoutsBlock(providerConfig["timeouts"])
+	if err != nil {
+		diags = append(diags, &tfprotov5.Diagnostic{
+			Severity: tfprotov5.DiagnosticSeverityInvalid,
+			Summary:  "Invalid attribute in provider configuration",
+			Detail:   "'timeouts' " + err.Error(),
+		})
+	}
+
 	if len(diags) > 0 {
 		response.Diagnostics = diags
 		return response, nil
@@ -391,109 +866,54 @@ func (s *RawProviderServer) ConfigureProvider(ctx context.Context, req *tfprotov
 		overrides.AuthInfo.Token = token
 	}
 
-	var execObj map[string]tftypes.Value
+	// Handle 'exec' attribute
+	//
+	execCfg, execExt, err := parseExecConfig(providerConfig["exec"])
+	if err != nil {
+		response.Diagnostics = append(response.Diagnostics, &tfprotov5.Diagnostic{
+			Severity:  tfprotov5.DiagnosticSeverityInvalid,
+			Summary:   "Invalid attribute in provider configuration",
+			Detail:    err.Error(),
+			Attribute: err.(*execConfigError).path(),
+		})
+		return response, nil
+	}
+	if execExt != nil {
+		overrides.ClusterInfo.Extensions = map[string]runtime.Object{execClusterExtensionKey: execExt}
+	}
+	overrides.AuthInfo.Exec = execCfg
 
-	if !providerConfig["exec"].IsNull() && providerConfig["exec"].IsKnown() {
-		err = providerConfig["exec"].As(&execObj)
-		if err != nil {
-			// invalid attribute type - this shouldn't happen, bail out for now
-			response.Diagnostics = append(response.Diagnostics, &tfprotov5.Diagnostic{
-				Severity: tfprotov5.DiagnosticSeverityError,
-				Summary:  "Provider configuration: failed to assert type of 'exec' value",
-				Detail:   err.Error(),
-			})
-			return response, nil
-		}
-		execCfg := clientcmdapi.ExecConfig{}
-		if !execObj["api_version"].IsNull() && execObj["api_version"].IsKnown() {
-			var apiv string
-			err = execObj["api_version"].As(&apiv)
-			if err != nil {
-				// invalid attribute type - this shouldn't happen, bail out for now
-				response.Diagnostics = append(response.Diagnostics, &tfprotov5.Diagnostic{
-					Severity: tfprotov5.DiagnosticSeverityError,
-					Summary:  "Provider configuration: failed to assert type of 'api_version' value",
-					Detail:   err.Error(),
-				})
-				return response, nil
-			}
-			execCfg.APIVersion = apiv
-		}
-		if !execObj["command"].IsNull() && execObj["command"].IsKnown() {
-			var cmd string
-			err = execObj["command"].As(&cmd)
-			if err != nil {
-				// invalid attribute type - this shouldn't happen, bail out for now
-				response.Diagnostics = append(response.Diagnostics, &tfprotov5.Diagnostic{
-					Severity: tfprotov5.DiagnosticSeverityError,
-					Summary:  "Provider configuration: failed to assert type of 'command' value",
-					Detail:   err.Error(),
-				})
-				return response, nil
-			}
-			execCfg.Command = cmd
-		}
-		if !execObj["args"].IsNull() && execObj["args"].IsKnown() {
-			var xcmdArgs []tftypes.Value
-			err = execObj["args"].As(&xcmdArgs)
-			if err != nil {
-				// invalid attribute type - this shouldn't happen, bail out for now
-				response.Diagnostics = append(response.Diagnostics, &tfprotov5.Diagnostic{
-					Severity: tfprotov5.DiagnosticSeverityError,
-					Summary:  "Provider configuration: failed to assert type of 'args' value",
-					Detail:   err.Error(),
-				})
-				return response, nil
-			}
-			execCfg.Args = make([]string, 0, len(xcmdArgs))
-			for _, arg := range xcmdArgs {
-				var v string
-				err := arg.As(&v)
-				if err != nil {
-					// invalid attribute type - this shouldn't happen, bail out for now
-					response.Diagnostics = append(response.Diagnostics, &tfprotov5.Diagnostic{
-						Severity: tfprotov5.DiagnosticSeverityError,
-						Summary:  "Provider configuration: failed to assert type of element in 'args' value",
-						Detail:   err.Error(),
-					})
-					return response, nil
-				}
-				execCfg.Args = append(execCfg.Args, v)
+	// Handle 'impersonate' block
+	//
+	impersonate, impersonateUID, err := parseImpersonateBlock(providerConfig["impersonate"])
+	if err != nil {
+		response.Diagnostics = append(response.Diagnostics, &tfprotov5.Diagnostic{
+			Severity: tfprotov5.DiagnosticSeverityInvalid,
+			Summary:  "Invalid attribute in provider configuration",
+			Detail:   "'impersonate' " + err.Error(),
+		})
+		return response, nil
+	}
+
+	// fall back to KUBECONFIG when neither a kubeconfig file nor the API server and credentials are configured,
+	// so that ambient credentials are never mixed into an explicit configuration. Like kubectl, files in it
+	// that don't exist are skipped.
+	if loader.ExplicitPath == "" && len(loader.Precedence) == 0 && !hasInlineClusterConfig(overrides) {
+		for _, cp := range filepath.SplitList(os.Getenv("KUBECONFIG")) {
+			if cp == "" {
+				continue
 			}
-		}
-		if !execObj["env"].IsNull() && execObj["env"].IsKnown() {
-			var xcmdEnvs map[string]tftypes.Value
-			err = execObj["env"].As(&xcmdEnvs)
+			cpAbs, err := homedir.Expand(cp)
 			if err != nil {
-				// invalid attribute type - this shouldn't happen, bail out for now
 				response.Diagnostics = append(response.Diagnostics, &tfprotov5.Diagnostic{
-					Severity: tfprotov5.DiagnosticSeverityError,
-					Summary:  "Provider configuration: failed to assert type of element in 'env' value",
-					Detail:   err.Error(),
+					Severity: tfprotov5.DiagnosticSeverityInvalid,
+					Summary:  "Invalid KUBECONFIG environment variable",
+					Detail:   "KUBECONFIG refers to an invalid path: " + err.Error(),
 				})
 				return response, nil
 			}
-			execCfg.Env = make([]clientcmdapi.ExecEnvVar, 0, len(xcmdEnvs))
-			for k, v := range xcmdEnvs {
-				var vs string
-				err = v.As(&vs)
-				if err != nil {
-					// invalid attribute type - this shouldn't happen, bail out for now
-					response.Diagnostics = append(response.Diagnostics, &tfprotov5.Diagnostic{
-						Severity: tfprotov5.DiagnosticSeverityError,
-						Summary:  "Provider configuration: failed to assert type of element in 'env' value",
-						Detail:   err.Error(),
-					})
-					return response, nil
-				}
-				execCfg.Env = append(execCfg.Env, clientcmdapi.ExecEnvVar{
-					Name:  k,
-					Value: vs,
-				})
-			}
+			loader.Precedence = append(loader.Precedence, cpAbs)
 		}
-
-		overrides.AuthInfo.Exec = &execCfg
 	}
 
 	cc := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loader, overrides)
@@ -501,7 +921,8 @@ func (s *RawProviderServer) ConfigureProvider(ctx context.Context, req *tfprotov
 	if err != nil {
 		s.logger.Error("[Configure]", "Failed to load config:", spew.Sdump(cc))
 		if errors.Is(err, clientcmd.ErrEmptyConfig) {
-			// this is a terrible fix for if the configuration is a calculated value
+			// no credentials were configured at all - data sources which work offline remain usable,
+			// anything that needs the API server is rejected by checkValidCredentials
 			return response, nil
 		}
 		response.Diagnostics = append(response.Diagnostics, &tfprotov5.Diagnostic{
@@ -512,15 +933,51 @@ func (s *RawProviderServer) ConfigureProvider(ctx context.Context, req *tfprotov
 		return response, nil
 	}
 
+	if impersonate.UserName != "" {
+		clientConfig.Impersonate = impersonate
+	}
+
+	// client-side throttling settings - the API clients are only built later, on first use
+	clientConfig.QPS = float32(qps)
+	clientConfig.Burst = int(burst)
+	clientConfig.Timeout = timeout
+
 	if s.logger.IsTrace() {
 		clientConfig.WrapTransport = loggingTransport
 	}
 
+	if impersonateUID != "" {
+		// rest.ImpersonationConfig has no UID field in this version of client-go,
+		// so the header is set by a transport wrapper instead
+		wt := clientConfig.WrapTransport
+		clientConfig.WrapTransport = func(rt http.RoundTripper) http.RoundTripper {
+			if wt != nil {
+				rt = wt(rt)
+			}
+			return impersonateUIDTransport(impersonateUID, rt)
+		}
+	}
+
 	codec := runtime.NoopEncoder{Decoder: scheme.Codecs.UniversalDecoder()}
 	clientConfig.NegotiatedSerializer = serializer.NegotiatedSerializerWrapper(runtime.SerializerInfo{Serializer: codec})
 
-	s.logger.Trace("[Configure]", "[ClientConfig]", spew.Sdump(*clientConfig))
+	// rest.Config redacts the exec plugin configuration when printed, which modifies the
+	// ExecProvider shared by its shallow copies, so only a detached copy is logged
+	logConfig := *clientConfig
+	if logConfig.ExecProvider != nil {
+		ep := *logConfig.ExecProvider
+		logConfig.ExecProvider = &ep
+	}
+	s.logger.Trace("[Configure]", "[ClientConfig]", spew.Sdump(logConfig))
 	s.clientConfig = clientConfig
+	s.defaultNamespace = defaultNamespace
+	s.defaultLabels = defaultMetadata["default_labels"]
+	s.defaultAnnotations = defaultMetadata["default_annotations"]
+	s.fieldManagerDefaults = fieldManagerDefaults
+	s.timeoutDefaults = timeoutDefaults
+	s.waitPollInterval = pollInterval
+	s.ignoreLabels = ignoreMetadata["ignore_labels"]
+	s.ignoreAnnotations = ignoreMetadata["ignore_annotations"]
 
 	response.Diagnostics = append(response.Diagnostics, &tfprotov5.Diagnostic{
 		Severity: tfprotov5.DiagnosticSeverityWarning,
@@ -530,3 +987,94 @@ func (s *RawProviderServer) ConfigureProvider(ctx context.Context, req *tfprotov
 
 	return response, nil
 }
+
+// readProviderConfigFile returns the contents of a file referenced in the provider configuration
+func readProviderConfigFile(path string) (string, error) {
+	p, err := homedir.Expand(path)
+	if err != nil {
+		return "", err
+	}
+	c, err := ioutil.ReadFile(p)
+	if err != nil {
+		return "", err
+	}
+	return string(c), nil
+}
+
+// hasInlineClusterConfig reports whether the provider configuration sets the API server or credentials directly
+func hasInlineClusterConfig(overrides *clientcmd.ConfigOverrides) bool {
+	return overrides.ClusterInfo.Server != "" ||
+		overrides.AuthInfo.Token != "" ||
+		overrides.AuthInfo.Username != "" ||
+		len(overrides.AuthInfo.ClientCertificateData) != 0 ||
+		len(overrides.AuthInfo.ClientKeyData) != 0 ||
+		overrides.AuthInfo.Exec != nil
+}
+
+// checkObjectAttributeNames makes sure an object value from the provider configuration
+// only has attributes with the expected names
+func checkObjectAttributeNames(obj map[string]tftypes.Value, names ...string) error {
+	for k := range obj {
+		known := false
+		for _, n := range names {
+			if k == n {
+				known = true
+				break
+			}
+		}
+		if !known {
+			return fmt.Errorf("has unsupported attribute %q, supported attributes are: %s", k, strings.Join(names, ", "))
+		}
+	}
+	return nil
+}
+
+// providerConfigStringMap returns the known entries of a map of strings from the provider configuration
+func providerConfigStringMap(v tftypes.Value) (map[string]string, error) {
+	if v.IsNull() || !v.IsKnown() {
+		return nil, nil
+	}
+	var vals map[string]tftypes.Value
+	err := v.As(&vals)
+	if err != nil {
+		return nil, err
+	}
+	m := make(map[string]string, len(vals))
+	for k, ev := range vals {
+		if ev.IsNull() || !ev.IsKnown() {
+			continue
+		}
+		var s string
+		err = ev.As(&s)
+		if err != nil {
+			return nil, err
+		}
+		m[k] = s
+	}
+	return m, nil
+}
+
+// providerConfigStringList returns the known elements of a list of strings from the provider configuration
+func providerConfigStringList(v tftypes.Value) ([]string, error) {
+	if v.IsNull() || !v.IsKnown() {
+		return nil, nil
+	}
+	var vals []tftypes.Value
+	err := v.As(&vals)
+	if err != nil {
+		return nil, err
+	}
+	l := make([]string, 0, len(vals))
+	for _, ev := range vals {
+		if ev.IsNull() || !ev.IsKnown() {
+			continue
+		}
+		var s string
+		err = ev.As(&s)
+		if err != nil {
+			return nil, err
+		}
+		l = append(l, s)
+	}
+	return l, nil
+}
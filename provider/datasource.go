@@ -0,0 +1,200 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/davecgh/go-spew/spew"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// ValidateDataSourceConfig function
+func (s *RawProviderServer) ValidateDataSourceConfig(ctx context.Context, req *tfprotov5.ValidateDataSourceConfigRequest) (*tfprotov5.ValidateDataSourceConfigResponse, error) {
+	s.logger.Trace("[ValidateDataSourceConfig][Request]\n%s\n", spew.Sdump(*req))
+	resp := &tfprotov5.ValidateDataSourceConfigResponse{}
+
+	dt, err := GetDataSourceType(req.TypeName)
+	if err != nil {
+		resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
+			Severity: tfprotov5.DiagnosticSeverityError,
+			Summary:  "Failed to determine data source type",
+			Detail:   err.Error(),
+		})
+		return resp, nil
+	}
+
+	config, err := req.Config.Unmarshal(dt)
+	if err != nil {
+		resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
+			Severity: tfprotov5.DiagnosticSeverityError,
+			Summary:  "Failed to unmarshal data source configuration",
+			Detail:   err.Error(),
+		})
+		return resp, nil
+	}
+
+	configVal := make(map[string]tftypes.Value)
+	err = config.As(&configVal)
+	if err != nil {
+		resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
+			Severity: tfprotov5.DiagnosticSeverityError,
+			Summary:  "Failed to extract data source configuration from SDK value",
+			Detail:   err.Error(),
+		})
+		return resp, nil
+	}
+
+	if apv, ok := configVal["api_version"]; ok && apv.IsKnown() && !apv.IsNull() {
+		var apvStr string
+		err = apv.As(&apvStr)
+		if err == nil {
+			_, err = schema.ParseGroupVersion(apvStr)
+		}
+		if err != nil {
+			resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
+				Severity:  tfprotov5.DiagnosticSeverityError,
+				Summary:   "Invalid attribute in data source configuration",
+				Detail:    fmt.Sprintf("'api_version' is not a valid API version: %s", err),
+				Attribute: tftypes.NewAttributePath().WithAttributeName("api_version"),
+			})
+		}
+	}
+
+	if ls, ok := configVal["label_selector"]; ok && ls.IsKnown() && !ls.IsNull() {
+		var lsStr string
+		err = ls.As(&lsStr)
+		if err == nil {
+			_, err = labels.Parse(lsStr)
+		}
+		if err != nil {
+			resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
+				Severity:  tfprotov5.DiagnosticSeverityError,
+				Summary:   "Invalid attribute in data source configuration",
+				Detail:    fmt.Sprintf("'label_selector' is not a valid label selector: %s", err),
+				Attribute: tftypes.NewAttributePath().WithAttributeName("label_selector"),
+			})
+		}
+	}
+
+	if fs, ok := configVal["field_selector"]; ok && fs.IsKnown() && !fs.IsNull() {
+		var fsStr string
+		err = fs.As(&fsStr)
+		if err == nil {
+			_, err = fields.ParseSelector(fsStr)
+		}
+		if err != nil {
+			resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
+				Severity:  tfprotov5.DiagnosticSeverityError,
+				Summary:   "Invalid attribute in data source configuration",
+				Detail:    fmt.Sprintf("'field_selector' is not a valid field selector: %s", err),
+				Attribute: tftypes.NewAttributePath().WithAttributeName("field_selector"),
+			})
+		}
+	}
+
+	if l, ok := configVal["limit"]; ok && l.IsKnown() && !l.IsNull() {
+		var lf big.Float
+		err = l.As(&lf)
+		if err == nil && (!lf.IsInt() || lf.Sign() < 0) {
+			err = fmt.Errorf("must be a non-negative integer, got %s", lf.String())
+		}
+		if err != nil {
+			resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
+				Severity:  tfprotov5.DiagnosticSeverityError,
+				Summary:   "Invalid attribute in data source configuration",
+				Detail:    fmt.Sprintf("'limit' is not valid: %s", err),
+				Attribute: tftypes.NewAttributePath().WithAttributeName("limit"),
+			})
+		}
+	}
+
+	if c, ok := configVal["content"]; ok && c.IsKnown() && !c.IsNull() {
+		var cStr string
+		err = c.As(&cStr)
+		if err == nil {
+			_, err = decodeManifests(cStr)
+		}
+		if err != nil {
+			resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
+				Severity:  tfprotov5.DiagnosticSeverityError,
+				Summary:   "Invalid attribute in data source configuration",
+				Detail:    fmt.Sprintf("'content' could not be decoded: %s", err),
+				Attribute: tftypes.NewAttributePath().WithAttributeName("content"),
+			})
+		}
+	}
+
+	return resp, nil
+}
+
+// ReadDataSource function
+func (s *RawProviderServer) ReadDataSource(ctx context.Context, req *tfprotov5.ReadDataSourceRequest) (*tfprotov5.ReadDataSourceResponse, error) {
+	s.logger.Trace("[ReadDataSource][Request]\n%s\n", spew.Sdump(*req))
+	resp := &tfprotov5.ReadDataSourceResponse{}
+
+	dt, err := GetDataSourceType(req.TypeName)
+	if err != nil {
+		resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
+			Severity: tfprotov5.DiagnosticSeverityError,
+			Summary:  "Failed to determine data source type",
+			Detail:   err.Error(),
+		})
+		return resp, nil
+	}
+
+	config, err := req.Config.Unmarshal(dt)
+	if err != nil {
+		resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
+			Severity: tfprotov5.DiagnosticSeverityError,
+			Summary:  "Failed to unmarshal data source configuration",
+			Detail:   err.Error(),
+		})
+		return resp, nil
+	}
+
+	if req.TypeName == "kubernetes_manifest_decode" {
+		// decoding happens entirely offline - no need for API credentials
+		return s.readManifestDecodeDataSource(ctx, config)
+	}
+
+	// test if credentials are valid - we're going to need them further down
+	resp.Diagnostics = append(resp.Diagnostics, s.checkValidCredentials(ctx)...)
+	if len(resp.Diagnostics) > 0 {
+		return resp, nil
+	}
+
+	switch req.TypeName {
+	case "kubernetes_resource":
+		return s.readResourceDataSource(ctx, config)
+	case "kubernetes_resources":
+		return s.readResourcesDataSource(ctx, config)
+	case "kubernetes_discovery":
+		return s.readDiscoveryDataSource(ctx, config)
+	case "kubernetes_schema":
+		return s.readSchemaDataSource(ctx, config)
+	}
+
+	resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
+		Severity: tfprotov5.DiagnosticSeverityError,
+		Summary:  "Unsupported data source",
+		Detail:   fmt.Sprintf("Data source %q is not supported by this provider", req.TypeName),
+	})
+	return resp, nil
+}
+
+// dataSourceStringAttribute extracts the value of a string attribute from a data source configuration.
+// Null or unknown values are returned as an empty string.
+func dataSourceStringAttribute(config map[string]tftypes.Value, name string) (string, error) {
+	var v string
+	att, ok := config[name]
+	if !ok || att.IsNull() || !att.IsKnown() {
+		return v, nil
+	}
+	err := att.As(&v)
+	return v, err
+}
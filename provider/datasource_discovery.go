@@ -0,0 +1,137 @@
+package provider
+
+import (
+	"context"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/discovery"
+)
+
+// readDiscoveryDataSource exposes the server version and the API groups and resources
+// served by the cluster, as reported by the discovery API
+func (s *RawProviderServer) readDiscoveryDataSource(ctx context.Context, config tftypes.Value) (*tfprotov5.ReadDataSourceResponse, error) {
+	resp := &tfprotov5.ReadDataSourceResponse{}
+
+	dc, err := s.getDiscoveryClient()
+	if err != nil {
+		resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
+			Severity: tfprotov5.DiagnosticSeverityError,
+			Summary:  "Failed to get discovery client",
+			Detail:   err.Error(),
+		})
+		return resp, nil
+	}
+
+	sv, err := dc.ServerVersion()
+	if err != nil {
+		resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
+			Severity: tfprotov5.DiagnosticSeverityError,
+			Summary:  "Failed to retrieve API server version",
+			Detail:   err.Error(),
+		})
+		return resp, nil
+	}
+
+	groups, resLists, err := dc.ServerGroupsAndResources()
+	if err != nil {
+		if !discovery.IsGroupDiscoveryFailedError(err) {
+			resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
+				Severity: tfprotov5.DiagnosticSeverityError,
+				Summary:  "Failed to retrieve API groups and resources",
+				Detail:   err.Error(),
+			})
+			return resp, nil
+		}
+		// some aggregated APIs can be temporarily unavailable - report what could be discovered
+		resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
+			Severity: tfprotov5.DiagnosticSeverityWarning,
+			Summary:  "Some API groups could not be discovered",
+			Detail:   err.Error(),
+		})
+	}
+
+	atts := config.Type().(tftypes.Object).AttributeTypes
+	svType := atts["server_version"]
+	apiGroupType := atts["api_groups"].(tftypes.Map).AttributeType
+	apiResourceType := atts["resources"].(tftypes.List).ElementType
+
+	svVal := tftypes.NewValue(svType, map[string]tftypes.Value{
+		"major":       tftypes.NewValue(tftypes.String, sv.Major),
+		"minor":       tftypes.NewValue(tftypes.String, sv.Minor),
+		"git_version": tftypes.NewValue(tftypes.String, sv.GitVersion),
+		"git_commit":  tftypes.NewValue(tftypes.String, sv.GitCommit),
+		"build_date":  tftypes.NewValue(tftypes.String, sv.BuildDate),
+		"platform":    tftypes.NewValue(tftypes.String, sv.Platform),
+	})
+
+	apiVersions := make([]tftypes.Value, 0)
+	apiGroups := make(map[string]tftypes.Value, len(groups))
+	for _, g := range groups {
+		versions := make([]tftypes.Value, 0, len(g.Versions))
+		for _, v := range g.Versions {
+			versions = append(versions, tftypes.NewValue(tftypes.String, v.Version))
+			apiVersions = append(apiVersions, tftypes.NewValue(tftypes.String, v.GroupVersion))
+		}
+		apiGroups[g.Name] = tftypes.NewValue(apiGroupType, map[string]tftypes.Value{
+			"preferred_version": tftypes.NewValue(tftypes.String, g.PreferredVersion.Version),
+			"versions":          tftypes.NewValue(tftypes.List{ElementType: tftypes.String}, versions),
+		})
+	}
+
+	resources := make([]tftypes.Value, 0)
+	for _, rl := range resLists {
+		for _, r := range rl.APIResources {
+			if strings.Contains(r.Name, "/") {
+				// skip subresources, like "deployments/scale"
+				continue
+			}
+			resources = append(resources, apiResourceToValue(apiResourceType, rl.GroupVersion, r))
+		}
+	}
+
+	stateVal := tftypes.NewValue(config.Type(), map[string]tftypes.Value{
+		"server_version": svVal,
+		"api_versions":   tftypes.NewValue(atts["api_versions"], apiVersions),
+		"api_groups":     tftypes.NewValue(atts["api_groups"], apiGroups),
+		"resources":      tftypes.NewValue(atts["resources"], resources),
+	})
+
+	state, err := tfprotov5.NewDynamicValue(stateVal.Type(), stateVal)
+	if err != nil {
+		resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
+			Severity: tfprotov5.DiagnosticSeverityError,
+			Summary:  "Failed to assemble data source state",
+			Detail:   err.Error(),
+		})
+		return resp, nil
+	}
+	resp.State = &state
+	return resp, nil
+}
+
+// apiResourceToValue converts a discovered API resource into an element of the 'resources' attribute
+func apiResourceToValue(t tftypes.Type, groupVersion string, r metav1.APIResource) tftypes.Value {
+	scope := "Cluster"
+	if r.Namespaced {
+		scope = "Namespaced"
+	}
+	verbs := make([]tftypes.Value, 0, len(r.Verbs))
+	for _, v := range r.Verbs {
+		verbs = append(verbs, tftypes.NewValue(tftypes.String, v))
+	}
+	shortNames := make([]tftypes.Value, 0, len(r.ShortNames))
+	for _, sn := range r.ShortNames {
+		shortNames = append(shortNames, tftypes.NewValue(tftypes.String, sn))
+	}
+	return tftypes.NewValue(t, map[string]tftypes.Value{
+		"name":          tftypes.NewValue(tftypes.String, r.Name),
+		"kind":          tftypes.NewValue(tftypes.String, r.Kind),
+		"group_version": tftypes.NewValue(tftypes.String, groupVersion),
+		"scope":         tftypes.NewValue(tftypes.String, scope),
+		"verbs":         tftypes.NewValue(tftypes.List{ElementType: tftypes.String}, verbs),
+		"short_names":   tftypes.NewValue(tftypes.List{ElementType: tftypes.String}, shortNames),
+	})
+}
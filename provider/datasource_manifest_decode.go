@@ -0,0 +1,195 @@
+package provider
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-provider-kubernetes-alpha/payload"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	k8syaml "k8s.io/apimachinery/pkg/util/yaml"
+	"sigs.k8s.io/yaml"
+)
+
+// readManifestDecodeDataSource decodes a multi-document YAML or JSON string
+// into manifests for the 'kubernetes_manifest_decode' data source
+func (s *RawProviderServer) readManifestDecodeDataSource(ctx context.Context, config tftypes.Value) (*tfprotov5.ReadDataSourceResponse, error) {
+	resp := &tfprotov5.ReadDataSourceResponse{}
+	contentPath := tftypes.NewAttributePath().WithAttributeName("content")
+
+	configVal := make(map[string]tftypes.Value)
+	err := config.As(&configVal)
+	if err != nil {
+		resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
+			Severity: tfprotov5.DiagnosticSeverityError,
+			Summary:  "Failed to extract data source configuration from SDK value",
+			Detail:   err.Error(),
+		})
+		return resp, nil
+	}
+
+	content, err := dataSourceStringAttribute(configVal, "content")
+	if err != nil {
+		resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
+			Severity:  tfprotov5.DiagnosticSeverityError,
+			Summary:   "Failed to extract 'content' value from data source configuration",
+			Detail:    err.Error(),
+			Attribute: contentPath,
+		})
+		return resp, nil
+	}
+
+	manifests, err := decodeManifests(content)
+	if err != nil {
+		resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
+			Severity:  tfprotov5.DiagnosticSeverityError,
+			Summary:   "Failed to decode manifests",
+			Detail:    err.Error(),
+			Attribute: contentPath,
+		})
+		return resp, nil
+	}
+
+	vals := make(map[string]tftypes.Value, len(manifests))
+	types := make(map[string]tftypes.Type, len(manifests))
+	for k, m := range manifests {
+		v, err := payload.ToTFValue(m, tftypes.DynamicPseudoType, tftypes.NewAttributePath())
+		if err != nil {
+			resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
+				Severity:  tfprotov5.DiagnosticSeverityError,
+				Summary:   fmt.Sprintf("Failed to convert manifest %q", k),
+				Detail:    err.Error(),
+				Attribute: contentPath,
+			})
+			return resp, nil
+		}
+		vals[k] = v
+		types[k] = v.Type()
+	}
+
+	// an object rather than a map, since each manifest has a different type
+	configVal["manifests"] = tftypes.NewValue(tftypes.Object{AttributeTypes: types}, vals)
+	stateVal := tftypes.NewValue(config.Type(), configVal)
+
+	state, err := tfprotov5.NewDynamicValue(stateVal.Type(), stateVal)
+	if err != nil {
+		resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
+			Severity: tfprotov5.DiagnosticSeverityError,
+			Summary:  "Failed to assemble data source state",
+			Detail:   err.Error(),
+		})
+		return resp, nil
+	}
+	resp.State = &state
+	return resp, nil
+}
+
+// decodeManifests parses a stream of YAML or JSON documents into Kubernetes objects,
+// keyed by "kind/namespace/name" (or "kind/name" for objects without a namespace).
+// Documents of kind "List" are expanded into their items.
+func decodeManifests(content string) (map[string]map[string]interface{}, error) {
+	manifests := make(map[string]map[string]interface{})
+	r := k8syaml.NewYAMLReader(bufio.NewReader(strings.NewReader(content)))
+	for i := 0; ; i++ {
+		doc, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("document %d: %s", i, err)
+		}
+		if len(bytes.TrimSpace(doc)) == 0 {
+			continue
+		}
+
+		var obj interface{}
+		err = yaml.Unmarshal(doc, &obj, func(d *json.Decoder) *json.Decoder {
+			d.UseNumber()
+			return d
+		})
+		if err != nil {
+			return nil, fmt.Errorf("document %d: %s", i, err)
+		}
+		if obj == nil {
+			// the document only contained comments
+			continue
+		}
+		m, ok := normalizeNumbers(obj).(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("document %d: not a Kubernetes object", i)
+		}
+
+		items := []map[string]interface{}{m}
+		if m["kind"] == "List" {
+			items = items[:0]
+			li, ok := m["items"].([]interface{})
+			if !ok && m["items"] != nil {
+				return nil, fmt.Errorf("document %d: 'items' of List is not a list", i)
+			}
+			for j, it := range li {
+				im, ok := it.(map[string]interface{})
+				if !ok {
+					return nil, fmt.Errorf("document %d: item %d of List is not a Kubernetes object", i, j)
+				}
+				items = append(items, im)
+			}
+		}
+
+		for _, it := range items {
+			key, err := manifestKey(it)
+			if err != nil {
+				return nil, fmt.Errorf("document %d: %s", i, err)
+			}
+			if _, ok := manifests[key]; ok {
+				return nil, fmt.Errorf("document %d: duplicate manifest %q", i, key)
+			}
+			manifests[key] = it
+		}
+	}
+	return manifests, nil
+}
+
+// manifestKey builds the "kind/namespace/name" key of a manifest
+func manifestKey(m map[string]interface{}) (string, error) {
+	uo := unstructured.Unstructured{Object: m}
+	if uo.GetAPIVersion() == "" || uo.GetKind() == "" {
+		return "", fmt.Errorf("manifest is missing 'apiVersion' or 'kind'")
+	}
+	if uo.GetName() == "" {
+		return "", fmt.Errorf("%s manifest is missing 'metadata.name'", uo.GetKind())
+	}
+	if uo.GetNamespace() == "" {
+		return strings.Join([]string{uo.GetKind(), uo.GetName()}, "/"), nil
+	}
+	return strings.Join([]string{uo.GetKind(), uo.GetNamespace(), uo.GetName()}, "/"), nil
+}
+
+// normalizeNumbers replaces json.Number values with int64 where the number is an integer
+// and float64 otherwise, so integers don't get turned into floats while decoding
+func normalizeNumbers(in interface{}) interface{} {
+	switch v := in.(type) {
+	case map[string]interface{}:
+		for k, e := range v {
+			v[k] = normalizeNumbers(e)
+		}
+		return v
+	case []interface{}:
+		for i, e := range v {
+			v[i] = normalizeNumbers(e)
+		}
+		return v
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i
+		}
+		f, _ := v.Float64()
+		return f
+	}
+	return in
+}
@@ -0,0 +1,130 @@
+package provider
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestDecodeManifests(t *testing.T) {
+	samples := []struct {
+		in  string
+		out map[string]map[string]interface{}
+		err bool
+	}{
+		{
+			in: `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: foo
+  namespace: default
+data:
+  answer: "42"
+---
+# only a comment
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+  namespace: prod
+spec:
+  replicas: 3
+  ratio: 0.5
+`,
+			out: map[string]map[string]interface{}{
+				"ConfigMap/default/foo": {
+					"apiVersion": "v1",
+					"kind":       "ConfigMap",
+					"metadata":   map[string]interface{}{"name": "foo", "namespace": "default"},
+					"data":       map[string]interface{}{"answer": "42"},
+				},
+				"Deployment/prod/web": {
+					"apiVersion": "apps/v1",
+					"kind":       "Deployment",
+					"metadata":   map[string]interface{}{"name": "web", "namespace": "prod"},
+					"spec":       map[string]interface{}{"replicas": int64(3), "ratio": float64(0.5)},
+				},
+			},
+		},
+		{
+			in: `{"apiVersion": "v1", "kind": "Namespace", "metadata": {"name": "prod"}}`,
+			out: map[string]map[string]interface{}{
+				"Namespace/prod": {
+					"apiVersion": "v1",
+					"kind":       "Namespace",
+					"metadata":   map[string]interface{}{"name": "prod"},
+				},
+			},
+		},
+		{
+			in: `
+apiVersion: v1
+kind: List
+items:
+- apiVersion: v1
+  kind: Namespace
+  metadata:
+    name: one
+- apiVersion: v1
+  kind: Namespace
+  metadata:
+    name: two
+`,
+			out: map[string]map[string]interface{}{
+				"Namespace/one": {
+					"apiVersion": "v1",
+					"kind":       "Namespace",
+					"metadata":   map[string]interface{}{"name": "one"},
+				},
+				"Namespace/two": {
+					"apiVersion": "v1",
+					"kind":       "Namespace",
+					"metadata":   map[string]interface{}{"name": "two"},
+				},
+			},
+		},
+		{
+			in:  "",
+			out: map[string]map[string]interface{}{},
+		},
+		{
+			// missing name
+			in:  "apiVersion: v1\nkind: Namespace\n",
+			err: true,
+		},
+		{
+			// duplicate manifest
+			in:  "apiVersion: v1\nkind: Namespace\nmetadata:\n  name: one\n---\napiVersion: v1\nkind: Namespace\nmetadata:\n  name: one\n",
+			err: true,
+		},
+		{
+			// not an object
+			in:  "- one\n- two\n",
+			err: true,
+		},
+		{
+			in:  "apiVersion: v1\nkind: [\n",
+			err: true,
+		},
+	}
+
+	for i, s := range samples {
+		t.Run(fmt.Sprintf("sample%d", i+1), func(t *testing.T) {
+			m, err := decodeManifests(s.in)
+			if s.err {
+				if err == nil {
+					t.Fatalf("expected error for %q", s.in)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if !reflect.DeepEqual(m, s.out) {
+				t.Fatalf("expected %#v, got %#v", s.out, m)
+			}
+		})
+	}
+}
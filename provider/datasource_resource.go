@@ -0,0 +1,187 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/davecgh/go-spew/spew"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-provider-kubernetes-alpha/morph"
+	"github.com/hashicorp/terraform-provider-kubernetes-alpha/payload"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// readResourceDataSource retrieves a single resource from the API, as designated
+// by the api_version, kind, namespace and name attributes of the 'kubernetes_resource' data source
+func (s *RawProviderServer) readResourceDataSource(ctx context.Context, config tftypes.Value) (*tfprotov5.ReadDataSourceResponse, error) {
+	resp := &tfprotov5.ReadDataSourceResponse{}
+
+	configVal := make(map[string]tftypes.Value)
+	err := config.As(&configVal)
+	if err != nil {
+		resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
+			Severity: tfprotov5.DiagnosticSeverityError,
+			Summary:  "Failed to extract data source configuration from SDK value",
+			Detail:   err.Error(),
+		})
+		return resp, nil
+	}
+
+	atts := make(map[string]string)
+	for _, k := range []string{"api_version", "kind", "namespace", "name"} {
+		atts[k], err = dataSourceStringAttribute(configVal, k)
+		if err != nil {
+			resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
+				Severity:  tfprotov5.DiagnosticSeverityError,
+				Summary:   fmt.Sprintf("Failed to extract '%s' value from data source configuration", k),
+				Detail:    err.Error(),
+				Attribute: tftypes.NewAttributePath().WithAttributeName(k),
+			})
+			return resp, nil
+		}
+	}
+	rnamespace := atts["namespace"]
+	rname := atts["name"]
+	rnn := types.NamespacedName{Namespace: rnamespace, Name: rname}.String()
+
+	rm, err := s.getRestMapper()
+	if err != nil {
+		resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
+			Severity: tfprotov5.DiagnosticSeverityError,
+			Summary:  "Failed to get RESTMapper client",
+			Detail:   err.Error(),
+		})
+		return resp, nil
+	}
+	client, err := s.getDynamicClient()
+	if err != nil {
+		resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
+			Severity: tfprotov5.DiagnosticSeverityError,
+			Summary:  "failed to get Dynamic client",
+			Detail:   err.Error(),
+		})
+		return resp, nil
+	}
+
+	gvk, err := GVKFromAPIVersionKind(atts["api_version"], atts["kind"], rm)
+	if err != nil {
+		resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
+			Severity: tfprotov5.DiagnosticSeverityError,
+			Summary:  "Failed to determine GroupVersionKind for data source",
+			Detail:   err.Error(),
+		})
+		return resp, nil
+	}
+	uo := unstructured.Unstructured{}
+	uo.SetGroupVersionKind(gvk)
+	gvr, err := GVRFromUnstructured(&uo, rm)
+	if err != nil {
+		resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
+			Severity: tfprotov5.DiagnosticSeverityError,
+			Summary:  "Failed to determine GroupVersionResource for data source",
+			Detail:   err.Error(),
+		})
+		return resp, nil
+	}
+	ns, err := IsResourceNamespaced(gvk, rm)
+	if err != nil {
+		resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
+			Severity: tfprotov5.DiagnosticSeverityError,
+			Summary:  fmt.Sprintf("Failed to discover scope of resource '%s'", rnn),
+			Detail:   err.Error(),
+		})
+		return resp, nil
+	}
+	nsPath := tftypes.NewAttributePath().WithAttributeName("namespace")
+	if ns && rnamespace == "" {
+		resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
+			Severity:  tfprotov5.DiagnosticSeverityError,
+			Summary:   "Namespace required",
+			Detail:    fmt.Sprintf("Resources of type '%s' require a namespace", gvk.String()),
+			Attribute: nsPath,
+		})
+		return resp, nil
+	}
+	if !ns && rnamespace != "" {
+		resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
+			Severity:  tfprotov5.DiagnosticSeverityError,
+			Summary:   "Cluster level resource cannot take namespace",
+			Detail:    fmt.Sprintf("Resources of type '%s' cannot have a namespace", gvk.String()),
+			Attribute: nsPath,
+		})
+		return resp, nil
+	}
+
+	objectType, err := s.TFTypeFromOpenAPI(ctx, gvk, true)
+	if err != nil {
+		return resp, fmt.Errorf("failed to determine resource type ID: %s", err)
+	}
+
+	var ro *unstructured.Unstructured
+	if ns {
+		ro, err = client.Resource(gvr).Namespace(rnamespace).Get(ctx, rname, metav1.GetOptions{})
+	} else {
+		ro, err = client.Resource(gvr).Get(ctx, rname, metav1.GetOptions{})
+	}
+
+	var obj tftypes.Value
+	switch {
+	case apierrors.IsNotFound(err):
+		// a missing resource is not an error - it's reflected by a null 'object' attribute
+		obj = tftypes.NewValue(objectType, nil)
+	case err != nil:
+		resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
+			Severity: tfprotov5.DiagnosticSeverityError,
+			Summary:  fmt.Sprintf("Cannot GET resource %s", rnn),
+			Detail:   err.Error(),
+		})
+		return resp, nil
+	default:
+		s.logger.Trace("[ReadDataSource]", "[API Response]", spew.Sdump(ro))
+		meta, ok := ro.Object["metadata"].(map[string]interface{})
+		if !ok {
+			resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
+				Severity: tfprotov5.DiagnosticSeverityError,
+				Summary:  fmt.Sprintf("Invalid API response for resource %s", rnn),
+				Detail:   "The object returned by the API server has no 'metadata' attribute.",
+			})
+			return resp, nil
+		}
+		delete(meta, "managedFields")
+
+		obj, err = payload.ToTFValue(ro.Object, objectType, tftypes.NewAttributePath())
+		if err != nil {
+			resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
+				Severity: tfprotov5.DiagnosticSeverityError,
+				Summary:  "Failed to convert API response to 'object' value",
+				Detail:   err.Error(),
+			})
+			return resp, nil
+		}
+		obj, err = morph.DeepUnknown(objectType, obj, tftypes.NewAttributePath())
+		if err != nil {
+			return resp, err
+		}
+		obj = morph.UnknownToNull(obj)
+	}
+
+	configVal["object"] = obj
+	stateVal := tftypes.NewValue(config.Type(), configVal)
+	s.logger.Trace("[ReadDataSource]", "new state value", spew.Sdump(stateVal))
+
+	state, err := tfprotov5.NewDynamicValue(stateVal.Type(), stateVal)
+	if err != nil {
+		resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
+			Severity: tfprotov5.DiagnosticSeverityError,
+			Summary:  "Failed to assemble data source state",
+			Detail:   err.Error(),
+		})
+		return resp, nil
+	}
+	resp.State = &state
+	return resp, nil
+}
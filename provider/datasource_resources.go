@@ -0,0 +1,209 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-provider-kubernetes-alpha/morph"
+	"github.com/hashicorp/terraform-provider-kubernetes-alpha/payload"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/dynamic"
+)
+
+// listPageSize is the number of resources requested from the API in each call
+// while paging through the results of a 'kubernetes_resources' data source
+const listPageSize int64 = 500
+
+// readResourcesDataSource lists resources of a given kind from the API, optionally restricted
+// by namespace and selectors, as configured in the 'kubernetes_resources' data source
+func (s *RawProviderServer) readResourcesDataSource(ctx context.Context, config tftypes.Value) (*tfprotov5.ReadDataSourceResponse, error) {
+	resp := &tfprotov5.ReadDataSourceResponse{}
+
+	configVal := make(map[string]tftypes.Value)
+	err := config.As(&configVal)
+	if err != nil {
+		resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
+			Severity: tfprotov5.DiagnosticSeverityError,
+			Summary:  "Failed to extract data source configuration from SDK value",
+			Detail:   err.Error(),
+		})
+		return resp, nil
+	}
+
+	atts := make(map[string]string)
+	for _, k := range []string{"api_version", "kind", "namespace", "label_selector", "field_selector"} {
+		atts[k], err = dataSourceStringAttribute(configVal, k)
+		if err != nil {
+			resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
+				Severity:  tfprotov5.DiagnosticSeverityError,
+				Summary:   fmt.Sprintf("Failed to extract '%s' value from data source configuration", k),
+				Detail:    err.Error(),
+				Attribute: tftypes.NewAttributePath().WithAttributeName(k),
+			})
+			return resp, nil
+		}
+	}
+	rnamespace := atts["namespace"]
+
+	var limit int64
+	if l, ok := configVal["limit"]; ok && l.IsKnown() && !l.IsNull() {
+		var lf big.Float
+		err = l.As(&lf)
+		if err != nil {
+			resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
+				Severity:  tfprotov5.DiagnosticSeverityError,
+				Summary:   "Failed to extract 'limit' value from data source configuration",
+				Detail:    err.Error(),
+				Attribute: tftypes.NewAttributePath().WithAttributeName("limit"),
+			})
+			return resp, nil
+		}
+		limit, _ = lf.Int64()
+	}
+
+	rm, err := s.getRestMapper()
+	if err != nil {
+		resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
+			Severity: tfprotov5.DiagnosticSeverityError,
+			Summary:  "Failed to get RESTMapper client",
+			Detail:   err.Error(),
+		})
+		return resp, nil
+	}
+	client, err := s.getDynamicClient()
+	if err != nil {
+		resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
+			Severity: tfprotov5.DiagnosticSeverityError,
+			Summary:  "failed to get Dynamic client",
+			Detail:   err.Error(),
+		})
+		return resp, nil
+	}
+
+	gvk, err := GVKFromAPIVersionKind(atts["api_version"], atts["kind"], rm)
+	if err != nil {
+		resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
+			Severity: tfprotov5.DiagnosticSeverityError,
+			Summary:  "Failed to determine GroupVersionKind for data source",
+			Detail:   err.Error(),
+		})
+		return resp, nil
+	}
+	uo := unstructured.Unstructured{}
+	uo.SetGroupVersionKind(gvk)
+	gvr, err := GVRFromUnstructured(&uo, rm)
+	if err != nil {
+		resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
+			Severity: tfprotov5.DiagnosticSeverityError,
+			Summary:  "Failed to determine GroupVersionResource for data source",
+			Detail:   err.Error(),
+		})
+		return resp, nil
+	}
+	ns, err := IsResourceNamespaced(gvk, rm)
+	if err != nil {
+		resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
+			Severity: tfprotov5.DiagnosticSeverityError,
+			Summary:  fmt.Sprintf("Failed to discover scope of resource '%s'", gvk.String()),
+			Detail:   err.Error(),
+		})
+		return resp, nil
+	}
+	if !ns && rnamespace != "" {
+		resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
+			Severity:  tfprotov5.DiagnosticSeverityError,
+			Summary:   "Cluster level resource cannot take namespace",
+			Detail:    fmt.Sprintf("Resources of type '%s' cannot have a namespace", gvk.String()),
+			Attribute: tftypes.NewAttributePath().WithAttributeName("namespace"),
+		})
+		return resp, nil
+	}
+
+	var rs dynamic.ResourceInterface
+	if ns && rnamespace != "" {
+		rs = client.Resource(gvr).Namespace(rnamespace)
+	} else {
+		// for namespaced resources this lists across all namespaces
+		rs = client.Resource(gvr)
+	}
+
+	objectType, err := s.TFTypeFromOpenAPI(ctx, gvk, true)
+	if err != nil {
+		return resp, fmt.Errorf("failed to determine resource type ID: %s", err)
+	}
+
+	var items []unstructured.Unstructured
+	opts := metav1.ListOptions{
+		LabelSelector: atts["label_selector"],
+		FieldSelector: atts["field_selector"],
+	}
+	for {
+		opts.Limit = listPageSize
+		if limit > 0 && limit-int64(len(items)) < listPageSize {
+			opts.Limit = limit - int64(len(items))
+		}
+		l, err := rs.List(ctx, opts)
+		if err != nil {
+			resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
+				Severity: tfprotov5.DiagnosticSeverityError,
+				Summary:  fmt.Sprintf("Cannot LIST resources of type '%s'", gvk.String()),
+				Detail:   err.Error(),
+			})
+			return resp, nil
+		}
+		items = append(items, l.Items...)
+		s.logger.Trace("[ReadDataSource]", "listed resources", len(l.Items), "continue", l.GetContinue())
+
+		if l.GetContinue() == "" || (limit > 0 && int64(len(items)) >= limit) {
+			break
+		}
+		opts.Continue = l.GetContinue()
+	}
+
+	objs := make([]tftypes.Value, 0, len(items))
+	objTypes := make([]tftypes.Type, 0, len(items))
+	for i, item := range items {
+		ip := tftypes.NewAttributePath().WithAttributeName("objects").WithElementKeyInt(int64(i))
+		if meta, ok := item.Object["metadata"].(map[string]interface{}); ok {
+			delete(meta, "managedFields")
+		}
+		obj, err := payload.ToTFValue(item.Object, objectType, tftypes.NewAttributePath())
+		if err != nil {
+			resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
+				Severity:  tfprotov5.DiagnosticSeverityError,
+				Summary:   fmt.Sprintf("Failed to convert resource '%s' to 'objects' element", item.GetName()),
+				Detail:    err.Error(),
+				Attribute: ip,
+			})
+			return resp, nil
+		}
+		obj, err = morph.DeepUnknown(objectType, obj, tftypes.NewAttributePath())
+		if err != nil {
+			return resp, err
+		}
+		obj = morph.UnknownToNull(obj)
+		objs = append(objs, obj)
+		objTypes = append(objTypes, obj.Type())
+	}
+
+	// a tuple rather than a list, since the Tuple workarounds in the OpenAPI types
+	// can leave each element with a slightly different concrete type
+	configVal["objects"] = tftypes.NewValue(tftypes.Tuple{ElementTypes: objTypes}, objs)
+	stateVal := tftypes.NewValue(config.Type(), configVal)
+
+	state, err := tfprotov5.NewDynamicValue(stateVal.Type(), stateVal)
+	if err != nil {
+		resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
+			Severity: tfprotov5.DiagnosticSeverityError,
+			Summary:  "Failed to assemble data source state",
+			Detail:   err.Error(),
+		})
+		return resp, nil
+	}
+	resp.State = &state
+	return resp, nil
+}
@@ -0,0 +1,116 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// readSchemaDataSource renders the tftypes.Type which manifests of a given GVK are morphed into,
+// as generated from either the CRD schema or the cluster OpenAPI spec
+func (s *RawProviderServer) readSchemaDataSource(ctx context.Context, config tftypes.Value) (*tfprotov5.ReadDataSourceResponse, error) {
+	resp := &tfprotov5.ReadDataSourceResponse{}
+
+	configVal := make(map[string]tftypes.Value)
+	err := config.As(&configVal)
+	if err != nil {
+		resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
+			Severity: tfprotov5.DiagnosticSeverityError,
+			Summary:  "Failed to extract data source configuration from SDK value",
+			Detail:   err.Error(),
+		})
+		return resp, nil
+	}
+
+	atts := make(map[string]string)
+	for _, k := range []string{"api_version", "kind"} {
+		atts[k], err = dataSourceStringAttribute(configVal, k)
+		if err != nil {
+			resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
+				Severity:  tfprotov5.DiagnosticSeverityError,
+				Summary:   fmt.Sprintf("Failed to extract '%s' value from data source configuration", k),
+				Detail:    err.Error(),
+				Attribute: tftypes.NewAttributePath().WithAttributeName(k),
+			})
+			return resp, nil
+		}
+	}
+
+	var status bool
+	if st, ok := configVal["include_status"]; ok && st.IsKnown() && !st.IsNull() {
+		err = st.As(&status)
+		if err != nil {
+			resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
+				Severity:  tfprotov5.DiagnosticSeverityError,
+				Summary:   "Failed to extract 'include_status' value from data source configuration",
+				Detail:    err.Error(),
+				Attribute: tftypes.NewAttributePath().WithAttributeName("include_status"),
+			})
+			return resp, nil
+		}
+	}
+
+	rm, err := s.getRestMapper()
+	if err != nil {
+		resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
+			Severity: tfprotov5.DiagnosticSeverityError,
+			Summary:  "Failed to get RESTMapper client",
+			Detail:   err.Error(),
+		})
+		return resp, nil
+	}
+	gvk, err := GVKFromAPIVersionKind(atts["api_version"], atts["kind"], rm)
+	if err != nil {
+		resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
+			Severity: tfprotov5.DiagnosticSeverityError,
+			Summary:  "Failed to determine GroupVersionKind for data source",
+			Detail:   err.Error(),
+		})
+		return resp, nil
+	}
+
+	objectType, err := s.TFTypeFromOpenAPI(ctx, gvk, status)
+	if err != nil {
+		resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
+			Severity: tfprotov5.DiagnosticSeverityError,
+			Summary:  fmt.Sprintf("Failed to generate type for '%s'", gvk.String()),
+			Detail:   err.Error(),
+		})
+		return resp, nil
+	}
+	if !objectType.Is(tftypes.Object{}) {
+		resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
+			Severity: tfprotov5.DiagnosticSeverityWarning,
+			Summary:  "This resource type does not have an associated OpenAPI schema.",
+			Detail:   fmt.Sprintf("No OpenAPI schema could be found for '%s'. Manifests of this type are used as-is, without type conversion.", gvk.String()),
+		})
+	}
+
+	tj, err := json.MarshalIndent(objectType, "", "  ")
+	if err != nil {
+		resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
+			Severity: tfprotov5.DiagnosticSeverityError,
+			Summary:  "Failed to encode type as JSON",
+			Detail:   err.Error(),
+		})
+		return resp, nil
+	}
+
+	configVal["type_json"] = tftypes.NewValue(tftypes.String, string(tj))
+	stateVal := tftypes.NewValue(config.Type(), configVal)
+
+	state, err := tfprotov5.NewDynamicValue(stateVal.Type(), stateVal)
+	if err != nil {
+		resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
+			Severity: tfprotov5.DiagnosticSeverityError,
+			Summary:  "Failed to assemble data source state",
+			Detail:   err.Error(),
+		})
+		return resp, nil
+	}
+	resp.State = &state
+	return resp, nil
+}
@@ -2,6 +2,9 @@ package provider
 
 import (
 	"fmt"
+	"regexp"
+	"strings"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -32,3 +35,52 @@ func APIStatusErrorToDiagnostics(s metav1.Status) []*tfprotov5.Diagnostic {
 	}
 	return diags
 }
+
+// conflictManagerRegexp extracts the field manager from the message of a server-side apply conflict,
+// e.g. `conflict with "kubectl-client-side-apply" using apps/v1`
+var conflictManagerRegexp = regexp.MustCompile(`^conflict with "([^"]*)"`)
+
+// FieldManagerConflictDiagnostics converts the conflicts of a server-side apply request into Terraform Diagnostics,
+// one for each field manager that owns conflicting fields. It returns no diagnostics for other errors.
+func FieldManagerConflictDiagnostics(s metav1.Status, fieldManager string) []*tfprotov5.Diagnostic {
+	if s.Reason != metav1.StatusReasonConflict || s.Details == nil {
+		return nil
+	}
+	var managers []string
+	fields := make(map[string][]string)
+	for _, c := range s.Details.Causes {
+		if c.Type != metav1.CauseTypeFieldManagerConflict {
+			continue
+		}
+		m := c.Message
+		if sm := conflictManagerRegexp.FindStringSubmatch(c.Message); sm != nil {
+			m = sm[1]
+		}
+		if _, ok := fields[m]; !ok {
+			managers = append(managers, m)
+		}
+		fields[m] = append(fields[m], c.Field)
+	}
+
+	var diags []*tfprotov5.Diagnostic
+	for _, m := range managers {
+		diags = append(diags, &tfprotov5.Diagnostic{
+			Severity: tfprotov5.DiagnosticSeverityError,
+			Summary:  fmt.Sprintf("Field manager conflict with %q", m),
+			Detail: fmt.Sprintf("The values applied by the field manager %q conflict with fields owned by %q:\n\n  %s\n\n"+
+				"Remove these fields from the manifest, or set 'force_conflicts = true' in the 'field_manager' block to take ownership of them.",
+				fieldManager, m, strings.Join(fields[m], "\n  ")),
+		})
+	}
+	return diags
+}
+
+// timeoutDiagnostic reports an operation on a resource that didn't complete within its timeout
+func timeoutDiagnostic(summary string, op string, timeout time.Duration, err error) *tfprotov5.Diagnostic {
+	return &tfprotov5.Diagnostic{
+		Severity: tfprotov5.DiagnosticSeverityError,
+		Summary:  summary,
+		Detail: fmt.Sprintf("%s\n\nThe %s timeout of %s has expired. It can be raised with the '%s' attribute of the 'timeouts' block.",
+			err, op, timeout, op),
+	}
+}
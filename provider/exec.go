@@ -0,0 +1,194 @@
+package provider
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+// execAttributes are the attributes supported in the 'exec' object of the provider configuration
+var execAttributes = []string{"api_version", "command", "env", "args", "install_hint", "provide_cluster_info", "interactive_mode", "cluster_info_extension"}
+
+// execConfigError is an invalid 'exec' object. The attribute is empty when the error isn't about a single attribute.
+type execConfigError struct {
+	attribute string
+	detail    string
+}
+
+func (e *execConfigError) Error() string {
+	if e.attribute == "" {
+		return "'exec' " + e.detail
+	}
+	return fmt.Sprintf("'exec.%s' %s", e.attribute, e.detail)
+}
+
+// path returns the path of the invalid attribute in the provider configuration
+func (e *execConfigError) path() *tftypes.AttributePath {
+	p := tftypes.NewAttributePath().WithAttributeName("exec")
+	if e.attribute != "" {
+		p = p.WithAttributeName(e.attribute)
+	}
+	return p
+}
+
+// parseExecConfig builds the configuration of an exec credential plugin from the 'exec' object of the provider,
+// along with the cluster extension passed to the plugin. Values that aren't set or not known yet are left empty.
+//
+// 'exec' is a dynamic attribute so that all its attributes can be optional, which means Terraform doesn't convert
+// its values: numbers and booleans are accepted wherever a string is expected, like they are for typed attributes.
+func parseExecConfig(v tftypes.Value) (*clientcmdapi.ExecConfig, runtime.Object, error) {
+	if v.IsNull() || !v.IsKnown() {
+		return nil, nil, nil
+	}
+	var execObj map[string]tftypes.Value
+	err := v.As(&execObj)
+	if err != nil {
+		return nil, nil, &execConfigError{detail: "must be an object"}
+	}
+	err = checkObjectAttributeNames(execObj, execAttributes...)
+	if err != nil {
+		return nil, nil, &execConfigError{detail: err.Error()}
+	}
+	set := func(name string) bool {
+		v, ok := execObj[name]
+		return ok && !v.IsNull() && v.IsKnown()
+	}
+	str := func(name string) (string, error) {
+		s, err := execConfigString(execObj[name])
+		if err != nil {
+			return "", &execConfigError{attribute: name, detail: err.Error()}
+		}
+		return s, nil
+	}
+
+	execCfg := &clientcmdapi.ExecConfig{}
+	if set("api_version") {
+		apiv, err := str("api_version")
+		if err != nil {
+			return nil, nil, err
+		}
+		switch apiv {
+		case "client.authentication.k8s.io/v1alpha1", "client.authentication.k8s.io/v1beta1":
+		case "client.authentication.k8s.io/v1":
+			return nil, nil, &execConfigError{attribute: "api_version", detail: "client.authentication.k8s.io/v1 is not supported by this version of the provider, use client.authentication.k8s.io/v1beta1"}
+		default:
+			return nil, nil, &execConfigError{attribute: "api_version", detail: fmt.Sprintf("%q is not a known version, must be one of client.authentication.k8s.io/v1alpha1 or client.authentication.k8s.io/v1beta1", apiv)}
+		}
+		execCfg.APIVersion = apiv
+	}
+	if set("command") {
+		execCfg.Command, err = str("command")
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	if set("args") {
+		var args []tftypes.Value
+		err = execObj["args"].As(&args)
+		if err != nil {
+			return nil, nil, &execConfigError{attribute: "args", detail: "must be a list of strings"}
+		}
+		execCfg.Args = make([]string, 0, len(args))
+		for i, a := range args {
+			s, err := execConfigString(a)
+			if err != nil {
+				return nil, nil, &execConfigError{attribute: "args", detail: fmt.Sprintf("element %d %s", i, err)}
+			}
+			execCfg.Args = append(execCfg.Args, s)
+		}
+	}
+	if set("env") {
+		var env map[string]tftypes.Value
+		err = execObj["env"].As(&env)
+		if err != nil {
+			return nil, nil, &execConfigError{attribute: "env", detail: "must be a map of strings"}
+		}
+		execCfg.Env = make([]clientcmdapi.ExecEnvVar, 0, len(env))
+		for k, ev := range env {
+			s, err := execConfigString(ev)
+			if err != nil {
+				return nil, nil, &execConfigError{attribute: "env", detail: fmt.Sprintf("element %q %s", k, err)}
+			}
+			execCfg.Env = append(execCfg.Env, clientcmdapi.ExecEnvVar{Name: k, Value: s})
+		}
+	}
+	if set("install_hint") {
+		execCfg.InstallHint, err = str("install_hint")
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	if set("provide_cluster_info") {
+		pci, err := str("provide_cluster_info")
+		if err == nil {
+			execCfg.ProvideClusterInfo, err = strconv.ParseBool(pci)
+		}
+		if err != nil {
+			return nil, nil, &execConfigError{attribute: "provide_cluster_info", detail: "must be a boolean"}
+		}
+		if execCfg.ProvideClusterInfo && execCfg.APIVersion == "client.authentication.k8s.io/v1alpha1" {
+			return nil, nil, &execConfigError{attribute: "provide_cluster_info", detail: "requires 'exec.api_version' client.authentication.k8s.io/v1beta1"}
+		}
+	}
+	if set("interactive_mode") {
+		im, err := str("interactive_mode")
+		if err != nil {
+			return nil, nil, err
+		}
+		// Terraform doesn't connect the provider to a terminal, so plugins never run interactively.
+		// This version of client-go has no setting for it, the value is only checked.
+		switch im {
+		case "Never", "IfAvailable":
+		case "Always":
+			return nil, nil, &execConfigError{attribute: "interactive_mode", detail: "cannot be \"Always\": the provider has no terminal to run the plugin interactively"}
+		default:
+			return nil, nil, &execConfigError{attribute: "interactive_mode", detail: fmt.Sprintf("%q is not valid, must be one of Never, IfAvailable or Always", im)}
+		}
+	}
+	var ext runtime.Object
+	if set("cluster_info_extension") {
+		raw, err := str("cluster_info_extension")
+		if err != nil {
+			return nil, nil, err
+		}
+		var extObj map[string]interface{}
+		err = json.Unmarshal([]byte(raw), &extObj)
+		if err == nil && extObj == nil {
+			err = errors.New("value is null")
+		}
+		if err != nil {
+			return nil, nil, &execConfigError{attribute: "cluster_info_extension", detail: "is not a JSON encoded object: " + err.Error()}
+		}
+		// passed to the plugin as 'spec.cluster.config' of the ExecCredential when 'provide_cluster_info' is set
+		ext = &runtime.Unknown{Raw: []byte(raw), ContentType: runtime.ContentTypeJSON}
+	}
+	return execCfg, ext, nil
+}
+
+// execConfigString returns a primitive value of the 'exec' object as a string
+func execConfigString(v tftypes.Value) (string, error) {
+	if v.IsNull() || !v.IsKnown() {
+		return "", nil
+	}
+	switch {
+	case v.Type().Is(tftypes.String):
+		var s string
+		err := v.As(&s)
+		return s, err
+	case v.Type().Is(tftypes.Number):
+		var n big.Float
+		err := v.As(&n)
+		return n.Text('f', -1), err
+	case v.Type().Is(tftypes.Bool):
+		var b bool
+		err := v.As(&b)
+		return strconv.FormatBool(b), err
+	}
+	return "", errors.New("must be a string")
+}
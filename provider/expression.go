@@ -0,0 +1,256 @@
+package provider
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"k8s.io/client-go/util/jsonpath"
+)
+
+// expressionOperators are the comparisons supported in 'wait_for' expressions,
+// longest first so that "<=" isn't read as "<"
+var expressionOperators = []string{"==", "!=", "<=", ">=", "<", ">"}
+
+// ExpressionMatcher evaluates the 'expression' attribute of 'wait_for' against an API object.
+// An expression is a JSONPath template like {.status.phase}, optionally compared to another
+// template or to a JSON value, as in {.status.readyReplicas} == {.spec.replicas}.
+type ExpressionMatcher struct {
+	expression string
+	left       *jsonpath.JSONPath
+	operator   string
+	right      *jsonpath.JSONPath
+	// literal is the right side of the comparison when it isn't a JSONPath template
+	literal interface{}
+}
+
+// parseExpressionMatcher builds an ExpressionMatcher, checking the syntax of the expression and its JSONPath templates
+func parseExpressionMatcher(expr string) (*ExpressionMatcher, error) {
+	e := &ExpressionMatcher{expression: expr}
+	tmpl, rest, err := splitJSONPathTemplate(strings.TrimSpace(expr))
+	if err != nil {
+		return nil, err
+	}
+	e.left, err = compileJSONPath(tmpl)
+	if err != nil {
+		return nil, err
+	}
+
+	rest = strings.TrimSpace(rest)
+	if rest == "" {
+		return e, nil
+	}
+	for _, op := range expressionOperators {
+		if strings.HasPrefix(rest, op) {
+			e.operator = op
+			break
+		}
+	}
+	if e.operator == "" {
+		return nil, fmt.Errorf("expected one of %s after %s, got %q", strings.Join(expressionOperators, ", "), tmpl, rest)
+	}
+
+	rest = strings.TrimSpace(rest[len(e.operator):])
+	if strings.HasPrefix(rest, "{") {
+		tmpl, tail, err := splitJSONPathTemplate(rest)
+		if err != nil {
+			return nil, err
+		}
+		if strings.TrimSpace(tail) != "" {
+			return nil, fmt.Errorf("unexpected %q after %s", strings.TrimSpace(tail), tmpl)
+		}
+		e.right, err = compileJSONPath(tmpl)
+		if err != nil {
+			return nil, err
+		}
+		return e, nil
+	}
+
+	err = json.Unmarshal([]byte(rest), &e.literal)
+	if err != nil {
+		return nil, fmt.Errorf("the right side of %q should be a JSONPath template like {.spec.replicas} or a JSON value like \"True\" or 3, got %s", e.operator, rest)
+	}
+	switch e.literal.(type) {
+	case float64:
+	case string, bool, nil:
+		if e.operator != "==" && e.operator != "!=" {
+			return nil, fmt.Errorf("%q can only compare numbers, got %s", e.operator, rest)
+		}
+	default:
+		return nil, fmt.Errorf("the right side of %q should be a string, number, boolean or null, got %s", e.operator, rest)
+	}
+	return e, nil
+}
+
+// splitJSONPathTemplate splits a JSONPath template in braces from the start of s, returning it along with the rest of s
+func splitJSONPathTemplate(s string) (string, string, error) {
+	if !strings.HasPrefix(s, "{") {
+		return "", "", fmt.Errorf("expected a JSONPath template like {.status.phase}, got %q", s)
+	}
+	depth := 0
+	var quote rune
+	escaped := false
+	for i, r := range s {
+		switch {
+		case escaped:
+			escaped = false
+		case quote != 0:
+			if r == '\\' {
+				escaped = true
+			} else if r == quote {
+				quote = 0
+			}
+		case r == '"' || r == '\'':
+			quote = r
+		case r == '{':
+			depth++
+		case r == '}':
+			depth--
+			if depth == 0 {
+				return s[:i+1], s[i+1:], nil
+			}
+		}
+	}
+	return "", "", fmt.Errorf("unterminated JSONPath template %q", s)
+}
+
+func compileJSONPath(tmpl string) (*jsonpath.JSONPath, error) {
+	// missing fields are expected while waiting, they don't make the template fail
+	jp := jsonpath.New("wait_for").AllowMissingKeys(true)
+	err := jp.Parse(tmpl)
+	if err != nil {
+		return nil, fmt.Errorf("invalid JSONPath template %s: %s", tmpl, err)
+	}
+	return jp, nil
+}
+
+func (e *ExpressionMatcher) String() string {
+	return fmt.Sprintf("expression %q", e.expression)
+}
+
+// match reports whether the expression holds for an API object, along with the values it was evaluated on.
+// When a template yields several values, as a filter on a list can, any of them can satisfy the comparison.
+func (e *ExpressionMatcher) match(obj map[string]interface{}) (bool, string) {
+	left, err := jsonPathValues(e.left, obj)
+	if err != nil {
+		return false, err.Error()
+	}
+	observed := formatJSONPathValues(left)
+	if e.operator == "" {
+		for _, l := range left {
+			if truthy(l) {
+				return true, observed
+			}
+		}
+		return false, observed
+	}
+
+	right := []interface{}{e.literal}
+	if e.right != nil {
+		right, err = jsonPathValues(e.right, obj)
+		if err != nil {
+			return false, err.Error()
+		}
+		observed = fmt.Sprintf("%s %s %s", observed, e.operator, formatJSONPathValues(right))
+	}
+	for _, l := range left {
+		for _, r := range right {
+			if compareValues(l, e.operator, r) {
+				return true, observed
+			}
+		}
+	}
+	return false, observed
+}
+
+func jsonPathValues(jp *jsonpath.JSONPath, obj map[string]interface{}) ([]interface{}, error) {
+	results, err := jp.FindResults(obj)
+	if err != nil {
+		return nil, err
+	}
+	var vals []interface{}
+	for _, r := range results {
+		for _, v := range r {
+			if v.IsValid() && v.CanInterface() {
+				vals = append(vals, v.Interface())
+			}
+		}
+	}
+	return vals, nil
+}
+
+func formatJSONPathValues(vals []interface{}) string {
+	if len(vals) == 0 {
+		return "(not present)"
+	}
+	var b []byte
+	if len(vals) == 1 {
+		b, _ = json.Marshal(vals[0])
+	} else {
+		b, _ = json.Marshal(vals)
+	}
+	return string(b)
+}
+
+// truthy reports whether a value satisfies an expression without comparison:
+// it has to be set, and not be false, an empty string or an empty list or object
+func truthy(v interface{}) bool {
+	switch tv := v.(type) {
+	case nil:
+		return false
+	case bool:
+		return tv
+	case string:
+		return tv != ""
+	case []interface{}:
+		return len(tv) > 0
+	case map[string]interface{}:
+		return len(tv) > 0
+	}
+	return true
+}
+
+// compareValues applies a comparison operator to two values decoded from JSON.
+// Numbers are compared by value, other values only support "==" and "!=".
+func compareValues(l interface{}, op string, r interface{}) bool {
+	lf, lnum := numberValue(l)
+	rf, rnum := numberValue(r)
+	if lnum && rnum {
+		switch op {
+		case "==":
+			return lf == rf
+		case "!=":
+			return lf != rf
+		case "<":
+			return lf < rf
+		case "<=":
+			return lf <= rf
+		case ">":
+			return lf > rf
+		case ">=":
+			return lf >= rf
+		}
+	}
+	switch op {
+	case "==":
+		return reflect.DeepEqual(l, r)
+	case "!=":
+		return !reflect.DeepEqual(l, r)
+	}
+	return false
+}
+
+func numberValue(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case int:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case float64:
+		return n, true
+	}
+	return 0, false
+}
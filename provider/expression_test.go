@@ -0,0 +1,73 @@
+package provider
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestExpressionMatcher(t *testing.T) {
+	type m = map[string]interface{}
+	deployment := m{
+		"metadata": m{"name": "web", "generation": int64(2)},
+		"spec":     m{"replicas": int64(3), "paused": false},
+		"status": m{
+			"observedGeneration": int64(2),
+			"readyReplicas":      int64(2),
+			"availableReplicas":  int64(3),
+			"conditions": []interface{}{
+				m{"type": "Progressing", "status": "True", "reason": "NewReplicaSetAvailable"},
+				m{"type": "Available", "status": "True", "reason": "MinimumReplicasAvailable"},
+			},
+		},
+	}
+
+	samples := []struct {
+		expr string
+		met  bool
+		err  bool
+	}{
+		{expr: "{.status.availableReplicas} == {.spec.replicas}", met: true},
+		{expr: "{.status.readyReplicas} == {.spec.replicas}"},
+		{expr: "{.status.readyReplicas} < {.spec.replicas}", met: true},
+		{expr: "{.status.observedGeneration} >= {.metadata.generation}", met: true},
+		{expr: "{.status.readyReplicas} >= 2", met: true},
+		{expr: "{.status.readyReplicas} > 2.5"},
+		{expr: `{.status.conditions[?(@.type=="Available")].status} == "True"`, met: true},
+		{expr: `{.status.conditions[?(@.type=="Available")].reason} != "MinimumReplicasAvailable"`},
+		{expr: `{.status.conditions[*].type} == "Progressing"`, met: true},
+		{expr: `{.status.conditions[?(@.type=="Ready")].status} == "True"`},
+		{expr: "{.status.readyReplicas} == \"2\""},
+		{expr: "{.spec.paused} == false", met: true},
+		{expr: "{.status.conditions}", met: true},
+		{expr: "{.spec.paused}"},
+		{expr: "{.status.loadBalancer.ingress}"},
+		{expr: "  {.status.readyReplicas}==2  ", met: true},
+		{expr: ".status.readyReplicas == 2", err: true},
+		{expr: "{.status.readyReplicas", err: true},
+		{expr: "{.status.readyReplicas} = 2", err: true},
+		{expr: "{.status.readyReplicas} == {.spec.replicas} + 1", err: true},
+		{expr: "{.status.readyReplicas} == two", err: true},
+		{expr: `{.status.phase} < "Running"`, err: true},
+		{expr: "{.status.readyReplicas} == [2]", err: true},
+		{expr: "{.status.conditions[?(@.type==}", err: true},
+	}
+
+	for i, s := range samples {
+		t.Run(fmt.Sprintf("sample%d", i+1), func(t *testing.T) {
+			e, err := parseExpressionMatcher(s.expr)
+			if s.err {
+				if err == nil {
+					t.Fatal("expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			met, observed := e.match(deployment)
+			if met != s.met {
+				t.Fatalf("expected expression met to be %v, got %v (%s)", s.met, met, observed)
+			}
+		})
+	}
+}
@@ -0,0 +1,87 @@
+package provider
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+const (
+	// defaultFieldManager is the field manager used for server-side apply when none is configured
+	defaultFieldManager string = "Terraform"
+	// maxFieldManagerLength is the longest field manager name accepted by the API server
+	maxFieldManagerLength int = 128
+)
+
+// fieldManagerSettings are the attributes set in a 'field_manager' block. Unset attributes are nil.
+type fieldManagerSettings struct {
+	name           *string
+	forceConflicts *bool
+}
+
+// parseFieldManagerBlock extracts the settings from the value of a 'field_manager' block.
+// Blocks that aren't set or not known yet produce empty settings.
+func parseFieldManagerBlock(v tftypes.Value) (fieldManagerSettings, error) {
+	var fm fieldManagerSettings
+	if v.IsNull() || !v.IsKnown() {
+		return fm, nil
+	}
+	var blocks []tftypes.Value
+	err := v.As(&blocks)
+	if err != nil {
+		return fm, err
+	}
+	if len(blocks) == 0 || blocks[0].IsNull() || !blocks[0].IsKnown() {
+		return fm, nil
+	}
+	var atts map[string]tftypes.Value
+	err = blocks[0].As(&atts)
+	if err != nil {
+		return fm, err
+	}
+	if n, ok := atts["name"]; ok && !n.IsNull() && n.IsKnown() {
+		var name string
+		err = n.As(&name)
+		if err != nil {
+			return fm, err
+		}
+		if name == "" {
+			return fm, errors.New("'name' cannot be empty")
+		}
+		if len(name) > maxFieldManagerLength {
+			return fm, fmt.Errorf("'name' cannot be longer than %d characters", maxFieldManagerLength)
+		}
+		fm.name = &name
+	}
+	if f, ok := atts["force_conflicts"]; ok && !f.IsNull() && f.IsKnown() {
+		var force bool
+		err = f.As(&force)
+		if err != nil {
+			return fm, err
+		}
+		fm.forceConflicts = &force
+	}
+	return fm, nil
+}
+
+// fieldManager returns the field manager and whether to force conflicts for a resource,
+// falling back to the settings of the provider for the attributes the resource doesn't set
+func (s *RawProviderServer) fieldManager(resourceBlock tftypes.Value) (string, bool, error) {
+	fm, err := parseFieldManagerBlock(resourceBlock)
+	if err != nil {
+		return "", false, err
+	}
+	name := s.fieldManagerDefaults.name
+	if fm.name != nil {
+		name = fm.name
+	}
+	force := s.fieldManagerDefaults.forceConflicts
+	if fm.forceConflicts != nil {
+		force = fm.forceConflicts
+	}
+	if name == nil {
+		return defaultFieldManager, force != nil && *force, nil
+	}
+	return *name, force != nil && *force, nil
+}
@@ -0,0 +1,127 @@
+package provider
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestFieldManager(t *testing.T) {
+	blockType := GetObjectTypeFromSchema(GetProviderResourceSchema()["kubernetes_manifest"]).(tftypes.Object).AttributeTypes["field_manager"]
+	elemType := blockType.(tftypes.List).ElementType
+	block := func(atts map[string]tftypes.Value) tftypes.Value {
+		vals := map[string]tftypes.Value{
+			"name":            tftypes.NewValue(tftypes.String, nil),
+			"force_conflicts": tftypes.NewValue(tftypes.Bool, nil),
+		}
+		for k, v := range atts {
+			vals[k] = v
+		}
+		return tftypes.NewValue(blockType, []tftypes.Value{tftypes.NewValue(elemType, vals)})
+	}
+	name := func(s string) *string { return &s }
+	force := func(b bool) *bool { return &b }
+
+	samples := []struct {
+		provider fieldManagerSettings
+		resource tftypes.Value
+		name     string
+		force    bool
+		err      bool
+	}{
+		{
+			resource: tftypes.NewValue(blockType, nil),
+			name:     "Terraform",
+		},
+		{
+			resource: tftypes.NewValue(blockType, []tftypes.Value{}),
+			name:     "Terraform",
+		},
+		{
+			provider: fieldManagerSettings{name: name("platform"), forceConflicts: force(true)},
+			resource: tftypes.NewValue(blockType, []tftypes.Value{}),
+			name:     "platform",
+			force:    true,
+		},
+		{
+			provider: fieldManagerSettings{name: name("platform"), forceConflicts: force(true)},
+			resource: block(map[string]tftypes.Value{"name": tftypes.NewValue(tftypes.String, "web")}),
+			name:     "web",
+			force:    true,
+		},
+		{
+			provider: fieldManagerSettings{forceConflicts: force(true)},
+			resource: block(map[string]tftypes.Value{"force_conflicts": tftypes.NewValue(tftypes.Bool, false)}),
+			name:     "Terraform",
+		},
+		{
+			resource: block(map[string]tftypes.Value{"name": tftypes.NewValue(tftypes.String, "")}),
+			err:      true,
+		},
+		{
+			resource: block(map[string]tftypes.Value{"name": tftypes.NewValue(tftypes.String, strings.Repeat("a", 129))}),
+			err:      true,
+		},
+	}
+
+	for i, s := range samples {
+		t.Run(fmt.Sprintf("sample%d", i+1), func(t *testing.T) {
+			ps := &RawProviderServer{logger: hclog.NewNullLogger(), fieldManagerDefaults: s.provider}
+			name, force, err := ps.fieldManager(s.resource)
+			if s.err {
+				if err == nil {
+					t.Fatal("expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if name != s.name || force != s.force {
+				t.Fatalf("expected field manager %q with force %v, got %q with force %v", s.name, s.force, name, force)
+			}
+		})
+	}
+}
+
+func TestFieldManagerConflictDiagnostics(t *testing.T) {
+	status := metav1.Status{
+		Status: metav1.StatusFailure,
+		Reason: metav1.StatusReasonConflict,
+		Details: &metav1.StatusDetails{
+			Name:  "web",
+			Group: "apps",
+			Kind:  "deployments",
+			Causes: []metav1.StatusCause{
+				{Type: metav1.CauseTypeFieldManagerConflict, Message: `conflict with "kubectl-client-side-apply" using apps/v1`, Field: ".spec.replicas"},
+				{Type: metav1.CauseTypeFieldManagerConflict, Message: `conflict with "hpa-controller" using apps/v1`, Field: ".spec.template.spec.containers[name=\"web\"].resources"},
+				{Type: metav1.CauseTypeFieldManagerConflict, Message: `conflict with "kubectl-client-side-apply" using apps/v1`, Field: ".metadata.labels.app"},
+			},
+		},
+	}
+
+	diags := FieldManagerConflictDiagnostics(status, "Terraform")
+	if len(diags) != 2 {
+		t.Fatalf("expected a diagnostic for each of the 2 field managers, got %d", len(diags))
+	}
+	if diags[0].Summary != `Field manager conflict with "kubectl-client-side-apply"` {
+		t.Fatalf("unexpected summary: %s", diags[0].Summary)
+	}
+	for _, f := range []string{".spec.replicas", ".metadata.labels.app"} {
+		if !strings.Contains(diags[0].Detail, f) {
+			t.Fatalf("expected conflicting field %s in detail: %s", f, diags[0].Detail)
+		}
+	}
+	if strings.Contains(diags[1].Detail, ".spec.replicas") {
+		t.Fatalf("unexpected field of another field manager in detail: %s", diags[1].Detail)
+	}
+
+	status.Reason = metav1.StatusReasonInvalid
+	if diags := FieldManagerConflictDiagnostics(status, "Terraform"); len(diags) > 0 {
+		t.Fatalf("expected no diagnostics for errors other than conflicts, got %d", len(diags))
+	}
+}
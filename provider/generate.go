@@ -0,0 +1,305 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+)
+
+// lastAppliedAnnotation is set by 'kubectl apply' and has no place in a Terraform manifest
+const lastAppliedAnnotation = "kubectl.kubernetes.io/last-applied-configuration"
+
+var invalidResourceNameChars = regexp.MustCompile(`[^a-z0-9_-]`)
+
+// Generate implements the 'generate' command. It renders 'kubernetes_manifest' resources and
+// matching import blocks for objects that already exist in the cluster.
+// Cluster credentials are loaded exactly as ConfigureProvider would, including the KUBE_* environment variables.
+func Generate(ctx context.Context, logger hclog.Logger, args []string, out io.Writer) error {
+	fs := flag.NewFlagSet("generate", flag.ContinueOnError)
+	configPath := fs.String("kubeconfig", "~/.kube/config", "path to the kubeconfig file (KUBE_CONFIG_PATH takes precedence)")
+	configContext := fs.String("context", "", "kubeconfig context to use (KUBE_CTX takes precedence)")
+	apiVersion := fs.String("api-version", "", "API version of the resources, e.g. apps/v1 (defaults to the preferred version of the kind)")
+	kind := fs.String("kind", "", "kind of the resources to generate, e.g. Deployment (required)")
+	namespace := fs.String("namespace", "", "only generate resources in this namespace (defaults to all namespaces)")
+	selector := fs.String("selector", "", "only generate resources matching this label selector")
+	includeOwned := fs.Bool("include-owned", false, "also generate resources managed by a controller, like the Pods of a ReplicaSet")
+	err := fs.Parse(args)
+	if err != nil {
+		return err
+	}
+	if *kind == "" {
+		return errors.New("the -kind flag is required")
+	}
+	if _, err := labels.Parse(*selector); err != nil {
+		return fmt.Errorf("invalid label selector: %s", err)
+	}
+
+	s := &RawProviderServer{logger: logger}
+	err = s.configureForGenerate(ctx, *configPath, *configContext)
+	if err != nil {
+		return err
+	}
+
+	rm, err := s.getRestMapper()
+	if err != nil {
+		return fmt.Errorf("failed to get RESTMapper client: %s", err)
+	}
+	var gvk schema.GroupVersionKind
+	if *apiVersion != "" {
+		gvk, err = GVKFromAPIVersionKind(*apiVersion, *kind, rm)
+	} else {
+		gvk, err = rm.KindFor(schema.GroupVersionResource{Resource: strings.ToLower(*kind)})
+	}
+	if err != nil {
+		return fmt.Errorf("failed to determine GroupVersionKind of %q: %s", *kind, err)
+	}
+	uo := unstructured.Unstructured{}
+	uo.SetGroupVersionKind(gvk)
+	gvr, err := GVRFromUnstructured(&uo, rm)
+	if err != nil {
+		return fmt.Errorf("failed to determine GroupVersionResource of %q: %s", gvk.String(), err)
+	}
+	ns, err := IsResourceNamespaced(gvk, rm)
+	if err != nil {
+		return fmt.Errorf("failed to discover scope of resource %q: %s", gvk.String(), err)
+	}
+	if !ns && *namespace != "" {
+		return fmt.Errorf("resources of type %q cannot have a namespace", gvk.String())
+	}
+
+	client, err := s.getDynamicClient()
+	if err != nil {
+		return fmt.Errorf("failed to get Dynamic client: %s", err)
+	}
+	var rs dynamic.ResourceInterface = client.Resource(gvr)
+	if ns && *namespace != "" {
+		rs = client.Resource(gvr).Namespace(*namespace)
+	}
+
+	var objs []map[string]interface{}
+	opts := metav1.ListOptions{LabelSelector: *selector, Limit: listPageSize}
+	for {
+		l, err := rs.List(ctx, opts)
+		if err != nil {
+			return fmt.Errorf("cannot LIST resources of type %q: %s", gvk.String(), err)
+		}
+		for _, item := range l.Items {
+			if len(item.GetOwnerReferences()) > 0 && !*includeOwned {
+				logger.Debug("[Generate]", "skipping owned resource", item.GetNamespace()+"/"+item.GetName())
+				continue
+			}
+			objs = append(objs, generateManifest(item.Object))
+		}
+		if l.GetContinue() == "" {
+			break
+		}
+		opts.Continue = l.GetContinue()
+	}
+
+	_, err = io.WriteString(out, generateHCL(objs))
+	return err
+}
+
+// configureForGenerate runs ConfigureProvider with a provider configuration that only sets
+// 'config_path' and 'config_context', so the command loads credentials the same way the provider does
+func (s *RawProviderServer) configureForGenerate(ctx context.Context, configPath, configContext string) error {
+	cfgType := GetTypeFromSchema(GetProviderConfigSchema())
+	cfgVals := make(map[string]tftypes.Value)
+	for k, t := range cfgType.(tftypes.Object).AttributeTypes {
+		cfgVals[k] = tftypes.NewValue(t, nil)
+	}
+	if configPath != "" {
+		cfgVals["config_path"] = tftypes.NewValue(tftypes.String, configPath)
+	}
+	if configContext != "" {
+		cfgVals["config_context"] = tftypes.NewValue(tftypes.String, configContext)
+	}
+	cfg, err := tfprotov5.NewDynamicValue(cfgType, tftypes.NewValue(cfgType, cfgVals))
+	if err != nil {
+		return fmt.Errorf("failed to assemble provider configuration: %s", err)
+	}
+
+	resp, err := s.ConfigureProvider(ctx, &tfprotov5.ConfigureProviderRequest{Config: &cfg})
+	if err != nil {
+		return err
+	}
+	var msgs []string
+	for _, d := range resp.Diagnostics {
+		if d.Severity != tfprotov5.DiagnosticSeverityWarning {
+			msgs = append(msgs, fmt.Sprintf("%s: %s", d.Summary, d.Detail))
+		}
+	}
+	if len(msgs) > 0 {
+		return errors.New(strings.Join(msgs, "\n"))
+	}
+	if s.clientConfig == nil {
+		return errors.New("no Kubernetes client configuration could be loaded")
+	}
+	return nil
+}
+
+// generateManifest strips an API object of the attributes that should not be part of a manifest
+func generateManifest(in map[string]interface{}) map[string]interface{} {
+	out := RemoveServerSideFields(in)
+	meta := out["metadata"].(map[string]interface{})
+	if an, ok := meta["annotations"].(map[string]interface{}); ok {
+		delete(an, lastAppliedAnnotation)
+		if len(an) == 0 {
+			delete(meta, "annotations")
+		}
+	}
+	return out
+}
+
+// generateHCL renders 'kubernetes_manifest' resources, each followed by an import block, for the supplied manifests
+func generateHCL(objs []map[string]interface{}) string {
+	var b strings.Builder
+	seen := make(map[string]int)
+	for i, obj := range objs {
+		uo := unstructured.Unstructured{Object: obj}
+		rid := ResourceID{
+			APIVersion: uo.GetAPIVersion(),
+			Kind:       uo.GetKind(),
+			Namespace:  uo.GetNamespace(),
+			Name:       uo.GetName(),
+		}
+		rn := generateResourceName(rid)
+		seen[rn]++
+		if seen[rn] > 1 {
+			rn = fmt.Sprintf("%s_%d", rn, seen[rn])
+		}
+
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		fmt.Fprintf(&b, "resource \"kubernetes_manifest\" %q {\n", rn)
+		b.WriteString("  provider = kubernetes-alpha\n\n")
+		b.WriteString("  manifest = ")
+		writeHCLValue(&b, obj, 1)
+		b.WriteString("\n}\n\n")
+
+		b.WriteString("import {\n")
+		b.WriteString("  provider = kubernetes-alpha\n")
+		fmt.Fprintf(&b, "  to       = kubernetes_manifest.%s\n", rn)
+		fmt.Fprintf(&b, "  id       = %s\n", hclQuote(rid.String()))
+		b.WriteString("}\n")
+	}
+	return b.String()
+}
+
+// generateResourceName builds a Terraform resource name out of the kind, namespace and name of an object
+func generateResourceName(rid ResourceID) string {
+	els := []string{rid.Kind}
+	if rid.Namespace != "" {
+		els = append(els, rid.Namespace)
+	}
+	els = append(els, rid.Name)
+	return invalidResourceNameChars.ReplaceAllString(strings.ToLower(strings.Join(els, "_")), "_")
+}
+
+// writeHCLValue renders a value decoded from JSON as an HCL expression
+func writeHCLValue(b *strings.Builder, in interface{}, indent int) {
+	pad := strings.Repeat("  ", indent)
+	switch v := in.(type) {
+	case nil:
+		b.WriteString("null")
+	case string:
+		b.WriteString(hclQuote(v))
+	case bool:
+		b.WriteString(strconv.FormatBool(v))
+	case int64:
+		b.WriteString(strconv.FormatInt(v, 10))
+	case int:
+		b.WriteString(strconv.Itoa(v))
+	case float64:
+		b.WriteString(strconv.FormatFloat(v, 'f', -1, 64))
+	case []interface{}:
+		if len(v) == 0 {
+			b.WriteString("[]")
+			return
+		}
+		b.WriteString("[\n")
+		for _, e := range v {
+			b.WriteString(pad + "  ")
+			writeHCLValue(b, e, indent+1)
+			b.WriteString(",\n")
+		}
+		b.WriteString(pad + "]")
+	case map[string]interface{}:
+		if len(v) == 0 {
+			b.WriteString("{}")
+			return
+		}
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		b.WriteString("{\n")
+		for _, k := range keys {
+			b.WriteString(pad + "  ")
+			if hclsyntax.ValidIdentifier(k) {
+				b.WriteString(k)
+			} else {
+				b.WriteString(hclQuote(k))
+			}
+			b.WriteString(" = ")
+			writeHCLValue(b, v[k], indent+1)
+			b.WriteString("\n")
+		}
+		b.WriteString(pad + "}")
+	default:
+		// not produced by the JSON decoder - fall back to a string representation
+		b.WriteString(hclQuote(fmt.Sprintf("%v", v)))
+	}
+}
+
+// hclQuote renders a string as a quoted HCL template which evaluates to exactly that string
+func hclQuote(s string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for i, r := range s {
+		switch r {
+		case '\\':
+			b.WriteString(`\\`)
+		case '"':
+			b.WriteString(`\"`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+			b.WriteString(`\r`)
+		case '\t':
+			b.WriteString(`\t`)
+		case '$', '%':
+			// escape template sequences
+			if strings.HasPrefix(s[i+1:], "{") {
+				b.WriteRune(r)
+			}
+			b.WriteRune(r)
+		default:
+			if r < 0x20 {
+				fmt.Fprintf(&b, `\u%04x`, r)
+			} else {
+				b.WriteRune(r)
+			}
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
@@ -0,0 +1,186 @@
+package provider
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"testing"
+
+	hcl "github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	ctyjson "github.com/zclconf/go-cty/cty/json"
+)
+
+func TestGenerateHCL(t *testing.T) {
+	samples := []struct {
+		in    map[string]interface{}
+		name  string
+		id    string
+		clean map[string]interface{}
+	}{
+		{
+			in: map[string]interface{}{
+				"apiVersion": "v1",
+				"kind":       "ConfigMap",
+				"metadata": map[string]interface{}{
+					"name":              "kube-root-ca.crt",
+					"namespace":         "default",
+					"uid":               "e7a6f1b4",
+					"resourceVersion":   "1234",
+					"creationTimestamp": "2021-05-01T00:00:00Z",
+					"annotations": map[string]interface{}{
+						lastAppliedAnnotation: "{}",
+					},
+				},
+				"data": map[string]interface{}{
+					"ca.crt":   "-----BEGIN CERTIFICATE-----\n\"quoted\"\t${not_a_template} %{if}\n",
+					"empty":    "",
+					"$literal": "100%",
+				},
+			},
+			name: "configmap_default_kube-root-ca_crt",
+			id:   "apiVersion=v1,kind=ConfigMap,namespace=default,name=kube-root-ca.crt",
+			clean: map[string]interface{}{
+				"apiVersion": "v1",
+				"kind":       "ConfigMap",
+				"metadata": map[string]interface{}{
+					"name":      "kube-root-ca.crt",
+					"namespace": "default",
+				},
+				"data": map[string]interface{}{
+					"ca.crt":   "-----BEGIN CERTIFICATE-----\n\"quoted\"\t${not_a_template} %{if}\n",
+					"empty":    "",
+					"$literal": "100%",
+				},
+			},
+		},
+		{
+			in: map[string]interface{}{
+				"apiVersion": "apps/v1",
+				"kind":       "Deployment",
+				"metadata": map[string]interface{}{
+					"name":      "web",
+					"namespace": "prod",
+					"labels":    map[string]interface{}{"app.kubernetes.io/name": "web"},
+				},
+				"spec": map[string]interface{}{
+					"replicas": int64(3),
+					"paused":   false,
+					"strategy": map[string]interface{}{},
+					"template": map[string]interface{}{
+						"spec": map[string]interface{}{
+							"containers": []interface{}{
+								map[string]interface{}{"name": "web", "args": []interface{}{"-v", "0.5"}, "ports": []interface{}{}},
+							},
+							"terminationGracePeriodSeconds": float64(30.5),
+							"nodeSelector":                  nil,
+						},
+					},
+				},
+				"status": map[string]interface{}{"readyReplicas": int64(3)},
+			},
+			name: "deployment_prod_web",
+			id:   "apiVersion=apps/v1,kind=Deployment,namespace=prod,name=web",
+			clean: map[string]interface{}{
+				"apiVersion": "apps/v1",
+				"kind":       "Deployment",
+				"metadata": map[string]interface{}{
+					"name":      "web",
+					"namespace": "prod",
+					"labels":    map[string]interface{}{"app.kubernetes.io/name": "web"},
+				},
+				"spec": map[string]interface{}{
+					"replicas": int64(3),
+					"paused":   false,
+					"strategy": map[string]interface{}{},
+					"template": map[string]interface{}{
+						"spec": map[string]interface{}{
+							"containers": []interface{}{
+								map[string]interface{}{"name": "web", "args": []interface{}{"-v", "0.5"}, "ports": []interface{}{}},
+							},
+							"terminationGracePeriodSeconds": float64(30.5),
+							"nodeSelector":                  nil,
+						},
+					},
+				},
+			},
+		},
+		{
+			in: map[string]interface{}{
+				"apiVersion": "v1",
+				"kind":       "Namespace",
+				"metadata":   map[string]interface{}{"name": "prod"},
+			},
+			name: "namespace_prod",
+			id:   "apiVersion=v1,kind=Namespace,name=prod",
+			clean: map[string]interface{}{
+				"apiVersion": "v1",
+				"kind":       "Namespace",
+				"metadata":   map[string]interface{}{"name": "prod"},
+			},
+		},
+	}
+
+	for i, s := range samples {
+		t.Run(fmt.Sprintf("sample%d", i+1), func(t *testing.T) {
+			src := generateHCL([]map[string]interface{}{generateManifest(s.in)})
+
+			f, diags := hclsyntax.ParseConfig([]byte(src), "generated.tf", hcl.Pos{Line: 1, Column: 1})
+			if diags.HasErrors() {
+				t.Fatalf("generated HCL does not parse: %s\n%s", diags.Error(), src)
+			}
+			blocks := f.Body.(*hclsyntax.Body).Blocks
+			if len(blocks) != 2 || blocks[0].Type != "resource" || blocks[1].Type != "import" {
+				t.Fatalf("expected a resource and an import block, got:\n%s", src)
+			}
+			if blocks[0].Labels[1] != s.name {
+				t.Fatalf("expected resource name %q, got %q", s.name, blocks[0].Labels[1])
+			}
+
+			idVal, diags := blocks[1].Body.Attributes["id"].Expr.Value(nil)
+			if diags.HasErrors() {
+				t.Fatalf("failed to evaluate import id: %s", diags.Error())
+			}
+			if idVal.AsString() != s.id {
+				t.Fatalf("expected import id %q, got %q", s.id, idVal.AsString())
+			}
+			if _, err := ParseResourceID(idVal.AsString()); err != nil {
+				t.Fatalf("import id does not parse: %s", err)
+			}
+
+			mVal, diags := blocks[0].Body.Attributes["manifest"].Expr.Value(nil)
+			if diags.HasErrors() {
+				t.Fatalf("failed to evaluate manifest: %s", diags.Error())
+			}
+			mj, err := ctyjson.Marshal(mVal, mVal.Type())
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got, expected interface{}
+			ej, _ := json.Marshal(s.clean)
+			json.Unmarshal(ej, &expected)
+			json.Unmarshal(mj, &got)
+			if !reflect.DeepEqual(expected, got) {
+				t.Fatalf("expected manifest %s, got %s", ej, mj)
+			}
+		})
+	}
+}
+
+func TestGenerateResourceNameUnique(t *testing.T) {
+	objs := []map[string]interface{}{
+		{"apiVersion": "v1", "kind": "ConfigMap", "metadata": map[string]interface{}{"name": "a.b", "namespace": "default"}},
+		{"apiVersion": "v1", "kind": "ConfigMap", "metadata": map[string]interface{}{"name": "a_b", "namespace": "default"}},
+	}
+	f, diags := hclsyntax.ParseConfig([]byte(generateHCL(objs)), "generated.tf", hcl.Pos{Line: 1, Column: 1})
+	if diags.HasErrors() {
+		t.Fatalf("generated HCL does not parse: %s", diags.Error())
+	}
+	blocks := f.Body.(*hclsyntax.Body).Blocks
+	if len(blocks) != 4 {
+		t.Fatalf("expected 4 blocks, got %d", len(blocks))
+	}
+	if blocks[0].Labels[1] != "configmap_default_a_b" || blocks[2].Labels[1] != "configmap_default_a_b_2" {
+		t.Fatalf("expected unique resource names, got %q and %q", blocks[0].Labels[1], blocks[2].Labels[1])
+	}
+}
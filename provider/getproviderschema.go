@@ -13,8 +13,11 @@ func (s *RawProviderServer) GetProviderSchema(ctx context.Context, req *tfprotov
 
 	resSchema := GetProviderResourceSchema()
 
+	dsSchema := GetProviderDataSourceSchema()
+
 	return &tfprotov5.GetProviderSchemaResponse{
-		Provider:        cfgSchema,
-		ResourceSchemas: resSchema,
+		Provider:          cfgSchema,
+		ResourceSchemas:   resSchema,
+		DataSourceSchemas: dsSchema,
 	}, nil
 }
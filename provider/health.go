@@ -0,0 +1,194 @@
+package provider
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// healthCheck reports whether an API object is ready, along with a short description of its state.
+// An error means the object reached a state it won't recover from, so there is no point in waiting any longer.
+type healthCheck func(obj map[string]interface{}) (bool, string, error)
+
+// healthChecks are the readiness checks used by 'wait_for { ready = true }'.
+// They are keyed by group and kind, as the checks hold for all versions of a kind.
+var healthChecks = map[schema.GroupKind]healthCheck{
+	{Group: "apps", Kind: "Deployment"}:                               deploymentReady,
+	{Group: "apps", Kind: "StatefulSet"}:                              statefulSetReady,
+	{Group: "apps", Kind: "DaemonSet"}:                                daemonSetReady,
+	{Group: "batch", Kind: "Job"}:                                     jobReady,
+	{Group: "", Kind: "PersistentVolumeClaim"}:                        persistentVolumeClaimReady,
+	{Group: "", Kind: "Service"}:                                      serviceReady,
+	{Group: "", Kind: "Namespace"}:                                    namespaceReady,
+	{Group: "apiextensions.k8s.io", Kind: "CustomResourceDefinition"}: conditionReady("Established"),
+	{Group: "apiregistration.k8s.io", Kind: "APIService"}:             conditionReady("Available"),
+}
+
+// healthCheckFor returns the readiness check for objects of the given apiVersion and kind
+func healthCheckFor(apiVersion, kind string) (healthCheck, error) {
+	gv, err := schema.ParseGroupVersion(apiVersion)
+	if err != nil {
+		return nil, err
+	}
+	hc, ok := healthChecks[gv.WithKind(kind).GroupKind()]
+	if !ok {
+		return nil, fmt.Errorf("there is no readiness check for resources of kind %q, wait for 'fields', 'condition' or 'expression' instead", gv.WithKind(kind).GroupKind().String())
+	}
+	return hc, nil
+}
+
+// generationObserved checks that the controller of an object has seen its latest changes
+func generationObserved(obj map[string]interface{}) (bool, string) {
+	generation, ok, _ := unstructured.NestedInt64(obj, "metadata", "generation")
+	if !ok {
+		return true, ""
+	}
+	observed, _, _ := unstructured.NestedInt64(obj, "status", "observedGeneration")
+	if observed < generation {
+		return false, fmt.Sprintf("waiting for generation %d to be observed, observed generation is %d", generation, observed)
+	}
+	return true, ""
+}
+
+// statusCondition returns the entry of 'status.conditions' with the given type, or nil
+func statusCondition(obj map[string]interface{}, conditionType string) map[string]interface{} {
+	conditions, _, _ := unstructured.NestedSlice(obj, "status", "conditions")
+	for _, ci := range conditions {
+		cond, ok := ci.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if t, _ := cond["type"].(string); t == conditionType {
+			return cond
+		}
+	}
+	return nil
+}
+
+// specReplicas returns the desired number of replicas of a workload, which defaults to 1
+func specReplicas(obj map[string]interface{}) int64 {
+	replicas, ok, _ := unstructured.NestedInt64(obj, "spec", "replicas")
+	if !ok {
+		return 1
+	}
+	return replicas
+}
+
+func deploymentReady(obj map[string]interface{}) (bool, string, error) {
+	if ok, msg := generationObserved(obj); !ok {
+		return false, msg, nil
+	}
+	if c := statusCondition(obj, "Progressing"); c != nil && c["reason"] == "ProgressDeadlineExceeded" {
+		return false, "", fmt.Errorf("deployment exceeded its progress deadline: %v", c["message"])
+	}
+	replicas := specReplicas(obj)
+	updated, _, _ := unstructured.NestedInt64(obj, "status", "updatedReplicas")
+	current, _, _ := unstructured.NestedInt64(obj, "status", "replicas")
+	available, _, _ := unstructured.NestedInt64(obj, "status", "availableReplicas")
+	switch {
+	case updated < replicas:
+		return false, fmt.Sprintf("%d of %d replicas updated", updated, replicas), nil
+	case current > updated:
+		return false, fmt.Sprintf("%d old replicas pending termination", current-updated), nil
+	case available < updated:
+		return false, fmt.Sprintf("%d of %d updated replicas available", available, updated), nil
+	}
+	return true, fmt.Sprintf("%d of %d replicas updated and available", available, replicas), nil
+}
+
+func statefulSetReady(obj map[string]interface{}) (bool, string, error) {
+	if ok, msg := generationObserved(obj); !ok {
+		return false, msg, nil
+	}
+	if strategy, _, _ := unstructured.NestedString(obj, "spec", "updateStrategy", "type"); strategy == "OnDelete" {
+		return true, "pods are updated on deletion", nil
+	}
+	replicas := specReplicas(obj)
+	ready, _, _ := unstructured.NestedInt64(obj, "status", "readyReplicas")
+	updated, _, _ := unstructured.NestedInt64(obj, "status", "updatedReplicas")
+	if ready < replicas {
+		return false, fmt.Sprintf("%d of %d replicas ready", ready, replicas), nil
+	}
+	if partition, ok, _ := unstructured.NestedInt64(obj, "spec", "updateStrategy", "rollingUpdate", "partition"); ok && partition > 0 {
+		if updated < replicas-partition {
+			return false, fmt.Sprintf("%d of %d replicas above the partition updated", updated, replicas-partition), nil
+		}
+		return true, fmt.Sprintf("%d replicas above the partition updated", updated), nil
+	}
+	currentRevision, _, _ := unstructured.NestedString(obj, "status", "currentRevision")
+	updateRevision, _, _ := unstructured.NestedString(obj, "status", "updateRevision")
+	if currentRevision != updateRevision {
+		return false, fmt.Sprintf("%d of %d replicas updated to revision %s", updated, replicas, updateRevision), nil
+	}
+	return true, fmt.Sprintf("%d of %d replicas ready at revision %s", ready, replicas, currentRevision), nil
+}
+
+func daemonSetReady(obj map[string]interface{}) (bool, string, error) {
+	if ok, msg := generationObserved(obj); !ok {
+		return false, msg, nil
+	}
+	if strategy, _, _ := unstructured.NestedString(obj, "spec", "updateStrategy", "type"); strategy == "OnDelete" {
+		return true, "pods are updated on deletion", nil
+	}
+	desired, _, _ := unstructured.NestedInt64(obj, "status", "desiredNumberScheduled")
+	updated, _, _ := unstructured.NestedInt64(obj, "status", "updatedNumberScheduled")
+	available, _, _ := unstructured.NestedInt64(obj, "status", "numberAvailable")
+	switch {
+	case updated < desired:
+		return false, fmt.Sprintf("%d of %d scheduled pods updated", updated, desired), nil
+	case available < desired:
+		return false, fmt.Sprintf("%d of %d scheduled pods available", available, desired), nil
+	}
+	return true, fmt.Sprintf("%d of %d scheduled pods updated and available", available, desired), nil
+}
+
+func jobReady(obj map[string]interface{}) (bool, string, error) {
+	if c := statusCondition(obj, "Failed"); c != nil && c["status"] == "True" {
+		return false, "", fmt.Errorf("job failed: %v: %v", c["reason"], c["message"])
+	}
+	if c := statusCondition(obj, "Complete"); c != nil && c["status"] == "True" {
+		return true, "job complete", nil
+	}
+	active, _, _ := unstructured.NestedInt64(obj, "status", "active")
+	succeeded, _, _ := unstructured.NestedInt64(obj, "status", "succeeded")
+	return false, fmt.Sprintf("%d pods active, %d succeeded", active, succeeded), nil
+}
+
+func persistentVolumeClaimReady(obj map[string]interface{}) (bool, string, error) {
+	phase, _, _ := unstructured.NestedString(obj, "status", "phase")
+	switch phase {
+	case "Bound":
+		return true, "phase Bound", nil
+	case "Lost":
+		return false, "", fmt.Errorf("the volume of the claim was lost")
+	}
+	return false, fmt.Sprintf("phase %q", phase), nil
+}
+
+func serviceReady(obj map[string]interface{}) (bool, string, error) {
+	if t, _, _ := unstructured.NestedString(obj, "spec", "type"); t != "LoadBalancer" {
+		return true, "", nil
+	}
+	ingress, _, _ := unstructured.NestedSlice(obj, "status", "loadBalancer", "ingress")
+	if len(ingress) == 0 {
+		return false, "waiting for the load balancer to be provisioned", nil
+	}
+	return true, fmt.Sprintf("load balancer has %d ingress points", len(ingress)), nil
+}
+
+func namespaceReady(obj map[string]interface{}) (bool, string, error) {
+	phase, _, _ := unstructured.NestedString(obj, "status", "phase")
+	return phase == "Active", fmt.Sprintf("phase %q", phase), nil
+}
+
+// conditionReady builds a check for objects which are ready when the condition with the given type is "True"
+func conditionReady(conditionType string) healthCheck {
+	return func(obj map[string]interface{}) (bool, string, error) {
+		c := statusCondition(obj, conditionType)
+		if c == nil {
+			return false, fmt.Sprintf("condition %q not present", conditionType), nil
+		}
+		return c["status"] == "True", fmt.Sprintf("condition %q is %v: %v", conditionType, c["status"], c["message"]), nil
+	}
+}
@@ -0,0 +1,166 @@
+package provider
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestHealthChecks(t *testing.T) {
+	type m = map[string]interface{}
+	object := func(apiVersion, kind string, generation int64, spec, status m) m {
+		return m{
+			"apiVersion": apiVersion,
+			"kind":       kind,
+			"metadata":   m{"name": "test", "generation": generation},
+			"spec":       spec,
+			"status":     status,
+		}
+	}
+	condition := func(t, status string) []interface{} {
+		return []interface{}{m{"type": t, "status": status, "reason": "Test", "message": "test"}}
+	}
+
+	samples := []struct {
+		obj   m
+		ready bool
+		err   bool
+	}{
+		// Deployment
+		{
+			obj: object("apps/v1", "Deployment", 2, m{"replicas": int64(3)},
+				m{"observedGeneration": int64(2), "replicas": int64(3), "updatedReplicas": int64(3), "availableReplicas": int64(3)}),
+			ready: true,
+		},
+		{
+			obj: object("apps/v1", "Deployment", 2, m{},
+				m{"observedGeneration": int64(2), "replicas": int64(1), "updatedReplicas": int64(1), "availableReplicas": int64(1)}),
+			ready: true,
+		},
+		{
+			// rollout not observed yet
+			obj: object("apps/v1", "Deployment", 3, m{"replicas": int64(3)},
+				m{"observedGeneration": int64(2), "replicas": int64(3), "updatedReplicas": int64(3), "availableReplicas": int64(3)}),
+		},
+		{
+			// old replicas still running
+			obj: object("apps/v1", "Deployment", 2, m{"replicas": int64(3)},
+				m{"observedGeneration": int64(2), "replicas": int64(4), "updatedReplicas": int64(3), "availableReplicas": int64(3)}),
+		},
+		{
+			obj: object("apps/v1", "Deployment", 2, m{"replicas": int64(3)},
+				m{"observedGeneration": int64(2), "replicas": int64(3), "updatedReplicas": int64(3), "availableReplicas": int64(2)}),
+		},
+		{
+			obj: object("apps/v1", "Deployment", 2, m{"replicas": int64(3)},
+				m{"observedGeneration": int64(2), "conditions": []interface{}{m{"type": "Progressing", "status": "False", "reason": "ProgressDeadlineExceeded"}}}),
+			err: true,
+		},
+		// StatefulSet
+		{
+			obj: object("apps/v1", "StatefulSet", 1, m{"replicas": int64(2)},
+				m{"observedGeneration": int64(1), "readyReplicas": int64(2), "updatedReplicas": int64(2), "currentRevision": "web-1", "updateRevision": "web-1"}),
+			ready: true,
+		},
+		{
+			obj: object("apps/v1", "StatefulSet", 1, m{"replicas": int64(2)},
+				m{"observedGeneration": int64(1), "readyReplicas": int64(2), "updatedReplicas": int64(1), "currentRevision": "web-1", "updateRevision": "web-2"}),
+		},
+		{
+			obj: object("apps/v1", "StatefulSet", 1, m{"replicas": int64(3), "updateStrategy": m{"type": "RollingUpdate", "rollingUpdate": m{"partition": int64(2)}}},
+				m{"observedGeneration": int64(1), "readyReplicas": int64(3), "updatedReplicas": int64(1), "currentRevision": "web-1", "updateRevision": "web-2"}),
+			ready: true,
+		},
+		// DaemonSet
+		{
+			obj: object("apps/v1", "DaemonSet", 1, m{},
+				m{"observedGeneration": int64(1), "desiredNumberScheduled": int64(3), "updatedNumberScheduled": int64(3), "numberAvailable": int64(3)}),
+			ready: true,
+		},
+		{
+			obj: object("apps/v1", "DaemonSet", 1, m{},
+				m{"observedGeneration": int64(1), "desiredNumberScheduled": int64(3), "updatedNumberScheduled": int64(2), "numberAvailable": int64(3)}),
+		},
+		// Job
+		{
+			obj:   object("batch/v1", "Job", 1, m{}, m{"succeeded": int64(1), "conditions": condition("Complete", "True")}),
+			ready: true,
+		},
+		{
+			obj: object("batch/v1", "Job", 1, m{}, m{"active": int64(1)}),
+		},
+		{
+			obj: object("batch/v1", "Job", 1, m{}, m{"failed": int64(6), "conditions": condition("Failed", "True")}),
+			err: true,
+		},
+		// PersistentVolumeClaim
+		{
+			obj:   object("v1", "PersistentVolumeClaim", 1, m{}, m{"phase": "Bound"}),
+			ready: true,
+		},
+		{
+			obj: object("v1", "PersistentVolumeClaim", 1, m{}, m{"phase": "Pending"}),
+		},
+		// Service
+		{
+			obj:   object("v1", "Service", 1, m{"type": "ClusterIP"}, m{}),
+			ready: true,
+		},
+		{
+			obj: object("v1", "Service", 1, m{"type": "LoadBalancer"}, m{"loadBalancer": m{}}),
+		},
+		{
+			obj:   object("v1", "Service", 1, m{"type": "LoadBalancer"}, m{"loadBalancer": m{"ingress": []interface{}{m{"ip": "10.0.0.1"}}}}),
+			ready: true,
+		},
+		// CustomResourceDefinition
+		{
+			obj:   object("apiextensions.k8s.io/v1", "CustomResourceDefinition", 1, m{}, m{"conditions": condition("Established", "True")}),
+			ready: true,
+		},
+		{
+			obj: object("apiextensions.k8s.io/v1beta1", "CustomResourceDefinition", 1, m{}, m{"conditions": condition("NamesAccepted", "True")}),
+		},
+		// APIService
+		{
+			obj:   object("apiregistration.k8s.io/v1", "APIService", 1, m{}, m{"conditions": condition("Available", "True")}),
+			ready: true,
+		},
+		{
+			obj: object("apiregistration.k8s.io/v1", "APIService", 1, m{}, m{"conditions": condition("Available", "False")}),
+		},
+		// Namespace
+		{
+			obj:   object("v1", "Namespace", 0, m{}, m{"phase": "Active"}),
+			ready: true,
+		},
+		{
+			obj: object("v1", "Namespace", 0, m{}, m{"phase": "Terminating"}),
+		},
+	}
+
+	for i, s := range samples {
+		t.Run(fmt.Sprintf("sample%d", i+1), func(t *testing.T) {
+			hc, err := healthCheckFor(s.obj["apiVersion"].(string), s.obj["kind"].(string))
+			if err != nil {
+				t.Fatal(err)
+			}
+			ready, msg, err := hc(s.obj)
+			if s.err {
+				if err == nil {
+					t.Fatal("expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if ready != s.ready {
+				t.Fatalf("expected ready to be %v, got %v (%s)", s.ready, ready, msg)
+			}
+		})
+	}
+
+	if _, err := healthCheckFor("v1", "ConfigMap"); err == nil {
+		t.Fatal("expected error for a kind without readiness check")
+	}
+}
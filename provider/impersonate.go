@@ -0,0 +1,107 @@
+package provider
+
+import (
+	"errors"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"k8s.io/client-go/rest"
+)
+
+// impersonateBlock is the 'impersonate' block of the provider
+func impersonateBlock() *tfprotov5.SchemaNestedBlock {
+	return &tfprotov5.SchemaNestedBlock{
+		TypeName: "impersonate",
+		Nesting:  tfprotov5.SchemaNestedBlockNestingModeList,
+		MaxItems: 1,
+		Block: &tfprotov5.SchemaBlock{
+			Description: "Identity to impersonate when making API requests.",
+			Attributes: []*tfprotov5.SchemaAttribute{
+				{
+					Name:        "user",
+					Type:        tftypes.String,
+					Optional:    true,
+					Description: "The user to impersonate. Required when any of the other attributes are set.",
+				},
+				{
+					Name:        "groups",
+					Type:        tftypes.List{ElementType: tftypes.String},
+					Optional:    true,
+					Description: "The groups to impersonate.",
+				},
+				{
+					Name:        "uid",
+					Type:        tftypes.String,
+					Optional:    true,
+					Description: "The UID of the user to impersonate. Requires Kubernetes 1.22 or above.",
+				},
+				{
+					Name:        "extra",
+					Type:        tftypes.Map{AttributeType: tftypes.List{ElementType: tftypes.String}},
+					Optional:    true,
+					Description: "Extra attributes of the user to impersonate, e.g. scopes.",
+				},
+			},
+		},
+	}
+}
+
+// parseImpersonateBlock extracts the identity to impersonate and its UID from the value of an 'impersonate' block.
+// Blocks that aren't set or not known yet produce an empty identity.
+func parseImpersonateBlock(v tftypes.Value) (rest.ImpersonationConfig, string, error) {
+	var imp rest.ImpersonationConfig
+	var uid string
+	if v.IsNull() || !v.IsKnown() {
+		return imp, uid, nil
+	}
+	var blocks []tftypes.Value
+	err := v.As(&blocks)
+	if err != nil {
+		return imp, uid, err
+	}
+	if len(blocks) == 0 || blocks[0].IsNull() || !blocks[0].IsKnown() {
+		return imp, uid, nil
+	}
+	var atts map[string]tftypes.Value
+	err = blocks[0].As(&atts)
+	if err != nil {
+		return imp, uid, err
+	}
+	if u, ok := atts["user"]; ok && !u.IsNull() && u.IsKnown() {
+		err = u.As(&imp.UserName)
+		if err != nil {
+			return imp, uid, err
+		}
+	}
+	if u, ok := atts["uid"]; ok && !u.IsNull() && u.IsKnown() {
+		err = u.As(&uid)
+		if err != nil {
+			return imp, uid, err
+		}
+	}
+	if g, ok := atts["groups"]; ok {
+		imp.Groups, err = providerConfigStringList(g)
+		if err != nil {
+			return imp, uid, err
+		}
+	}
+	if e, ok := atts["extra"]; ok && !e.IsNull() && e.IsKnown() {
+		var extra map[string]tftypes.Value
+		err = e.As(&extra)
+		if err != nil {
+			return imp, uid, err
+		}
+		imp.Extra = make(map[string][]string, len(extra))
+		for k, ev := range extra {
+			imp.Extra[k], err = providerConfigStringList(ev)
+			if err != nil {
+				return imp, uid, err
+			}
+		}
+	}
+	// the API server rejects impersonation of groups, UID or extra attributes without a user
+	if imp.UserName == "" && (len(imp.Groups) > 0 || len(imp.Extra) > 0 || uid != "") {
+		return imp, uid, errors.New("requires 'user' to be set when 'groups', 'uid' or 'extra' are set")
+	}
+	return imp, uid, nil
+}
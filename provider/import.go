@@ -0,0 +1,248 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/davecgh/go-spew/spew"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-provider-kubernetes-alpha/morph"
+	"github.com/hashicorp/terraform-provider-kubernetes-alpha/payload"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// ResourceID identifies a single Kubernetes resource as expressed in a Terraform import ID
+type ResourceID struct {
+	APIVersion string
+	Kind       string
+	Namespace  string
+	Name       string
+}
+
+// ParseResourceID parses an import ID of the form
+// "apiVersion=apps/v1,kind=Deployment,namespace=prod,name=web".
+// The namespace element is optional and should be left out for cluster-level resources.
+func ParseResourceID(id string) (ResourceID, error) {
+	var rid ResourceID
+	for _, el := range strings.Split(id, ",") {
+		kv := strings.SplitN(strings.TrimSpace(el), "=", 2)
+		if len(kv) != 2 || kv[1] == "" {
+			return ResourceID{}, fmt.Errorf("invalid element %q: expected a key=value pair", el)
+		}
+		switch kv[0] {
+		case "apiVersion":
+			rid.APIVersion = kv[1]
+		case "kind":
+			rid.Kind = kv[1]
+		case "namespace":
+			rid.Namespace = kv[1]
+		case "name":
+			rid.Name = kv[1]
+		default:
+			return ResourceID{}, fmt.Errorf("unknown key %q", kv[0])
+		}
+	}
+	for k, v := range map[string]string{"apiVersion": rid.APIVersion, "kind": rid.Kind, "name": rid.Name} {
+		if v == "" {
+			return ResourceID{}, fmt.Errorf("missing required key %q", k)
+		}
+	}
+	return rid, nil
+}
+
+// String renders the ResourceID in the import ID format understood by ParseResourceID
+func (r ResourceID) String() string {
+	els := []string{"apiVersion=" + r.APIVersion, "kind=" + r.Kind}
+	if r.Namespace != "" {
+		els = append(els, "namespace="+r.Namespace)
+	}
+	els = append(els, "name="+r.Name)
+	return strings.Join(els, ",")
+}
+
+// ImportResourceState reads a live resource from the cluster and turns it into resource state.
+// Both 'manifest' and 'object' are populated from the API response, after removing server-side fields.
+func (s *RawProviderServer) ImportResourceState(ctx context.Context, req *tfprotov5.ImportResourceStateRequest) (*tfprotov5.ImportResourceStateResponse, error) {
+	resp := &tfprotov5.ImportResourceStateResponse{}
+
+	rid, err := ParseResourceID(req.ID)
+	if err != nil {
+		resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
+			Severity: tfprotov5.DiagnosticSeverityError,
+			Summary:  "Invalid import ID",
+			Detail:   fmt.Sprintf("%s\n\nThe import ID should have the form: apiVersion=<api-version>,kind=<kind>,namespace=<namespace>,name=<name>\nOmit the namespace for cluster-level resources.", err),
+		})
+		return resp, nil
+	}
+
+	resp.Diagnostics = append(resp.Diagnostics, s.checkValidCredentials(ctx)...)
+	if len(resp.Diagnostics) > 0 {
+		return resp, nil
+	}
+
+	rt, err := GetResourceType(req.TypeName)
+	if err != nil {
+		resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
+			Severity: tfprotov5.DiagnosticSeverityError,
+			Summary:  "Failed to determine resource type",
+			Detail:   err.Error(),
+		})
+		return resp, nil
+	}
+
+	rm, err := s.getRestMapper()
+	if err != nil {
+		resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
+			Severity: tfprotov5.DiagnosticSeverityError,
+			Summary:  "Failed to get RESTMapper client",
+			Detail:   err.Error(),
+		})
+		return resp, nil
+	}
+	client, err := s.getDynamicClient()
+	if err != nil {
+		resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
+			Severity: tfprotov5.DiagnosticSeverityError,
+			Summary:  "failed to get Dynamic client",
+			Detail:   err.Error(),
+		})
+		return resp, nil
+	}
+
+	gvk, err := GVKFromAPIVersionKind(rid.APIVersion, rid.Kind, rm)
+	if err != nil {
+		resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
+			Severity: tfprotov5.DiagnosticSeverityError,
+			Summary:  "Failed to determine GroupVersionKind for imported resource",
+			Detail:   err.Error(),
+		})
+		return resp, nil
+	}
+
+	uo := unstructured.Unstructured{}
+	uo.SetGroupVersionKind(gvk)
+	gvr, err := GVRFromUnstructured(&uo, rm)
+	if err != nil {
+		resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
+			Severity: tfprotov5.DiagnosticSeverityError,
+			Summary:  "Failed to determine GroupVersionResource for imported resource",
+			Detail:   err.Error(),
+		})
+		return resp, nil
+	}
+
+	rnn := types.NamespacedName{Namespace: rid.Namespace, Name: rid.Name}.String()
+	ns, err := IsResourceNamespaced(gvk, rm)
+	if err != nil {
+		resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
+			Severity: tfprotov5.DiagnosticSeverityError,
+			Summary:  fmt.Sprintf("Failed to discover scope of resource '%s'", rnn),
+			Detail:   err.Error(),
+		})
+		return resp, nil
+	}
+	if ns && rid.Namespace == "" {
+		resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
+			Severity: tfprotov5.DiagnosticSeverityError,
+			Summary:  "Namespace required",
+			Detail:   fmt.Sprintf("Resources of type '%s' require a namespace in the import ID", gvk.String()),
+		})
+		return resp, nil
+	}
+	if !ns && rid.Namespace != "" {
+		resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
+			Severity: tfprotov5.DiagnosticSeverityError,
+			Summary:  "Cluster level resource cannot take namespace",
+			Detail:   fmt.Sprintf("Resources of type '%s' cannot have a namespace in the import ID", gvk.String()),
+		})
+		return resp, nil
+	}
+
+	var ro *unstructured.Unstructured
+	if ns {
+		ro, err = client.Resource(gvr).Namespace(rid.Namespace).Get(ctx, rid.Name, metav1.GetOptions{})
+	} else {
+		ro, err = client.Resource(gvr).Get(ctx, rid.Name, metav1.GetOptions{})
+	}
+	if err != nil {
+		summary := fmt.Sprintf("Cannot GET resource %s", rnn)
+		if apierrors.IsNotFound(err) {
+			summary = fmt.Sprintf("Resource %s does not exist", rnn)
+		}
+		resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
+			Severity: tfprotov5.DiagnosticSeverityError,
+			Summary:  summary,
+			Detail:   err.Error(),
+		})
+		return resp, nil
+	}
+	s.logger.Trace("[ImportResourceState]", "[API Response]", spew.Sdump(ro))
+
+	objectType, err := s.TFTypeFromOpenAPI(ctx, gvk, false)
+	if err != nil {
+		return resp, fmt.Errorf("failed to determine resource type ID: %s", err)
+	}
+
+	fo := RemoveServerSideFields(ro.Object)
+	// there is no manifest yet, so ignored metadata is left out of both 'manifest' and 'object'
+	s.removeIgnoredMetadata(fo, tftypes.NewValue(tftypes.DynamicPseudoType, nil))
+
+	// the manifest only carries the attributes actually present on the live resource,
+	// just as if it was written out in configuration
+	manifest, err := payload.ToTFValue(fo, tftypes.DynamicPseudoType, tftypes.NewAttributePath())
+	if err != nil {
+		resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
+			Severity: tfprotov5.DiagnosticSeverityError,
+			Summary:  "Failed to convert imported resource to 'manifest' value",
+			Detail:   err.Error(),
+		})
+		return resp, nil
+	}
+
+	nobj, err := payload.ToTFValue(fo, objectType, tftypes.NewAttributePath())
+	if err != nil {
+		resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
+			Severity: tfprotov5.DiagnosticSeverityError,
+			Summary:  "Failed to convert imported resource to 'object' value",
+			Detail:   err.Error(),
+		})
+		return resp, nil
+	}
+	nobj, err = morph.DeepUnknown(objectType, nobj, tftypes.NewAttributePath())
+	if err != nil {
+		return resp, err
+	}
+
+	stateType := rt.(tftypes.Object)
+	newState := map[string]tftypes.Value{
+		"manifest": manifest,
+		"object":   morph.UnknownToNull(nobj),
+	}
+	for k, t := range stateType.AttributeTypes {
+		if _, ok := newState[k]; !ok {
+			newState[k] = tftypes.NewValue(t, nil)
+		}
+	}
+	nsVal := tftypes.NewValue(stateType, newState)
+	s.logger.Trace("[ImportResourceState]", "new state value", spew.Sdump(nsVal))
+
+	impState, err := tfprotov5.NewDynamicValue(nsVal.Type(), nsVal)
+	if err != nil {
+		resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
+			Severity: tfprotov5.DiagnosticSeverityError,
+			Summary:  "Failed to assemble imported resource state",
+			Detail:   err.Error(),
+		})
+		return resp, nil
+	}
+	resp.ImportedResources = append(resp.ImportedResources, &tfprotov5.ImportedResource{
+		TypeName: req.TypeName,
+		State:    &impState,
+	})
+	return resp, nil
+}
@@ -0,0 +1,61 @@
+package provider
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestParseResourceID(t *testing.T) {
+	samples := []struct {
+		in  string
+		out ResourceID
+		err bool
+	}{
+		{
+			in:  "apiVersion=apps/v1,kind=Deployment,namespace=prod,name=web",
+			out: ResourceID{APIVersion: "apps/v1", Kind: "Deployment", Namespace: "prod", Name: "web"},
+		},
+		{
+			in:  "apiVersion=v1, kind=Namespace, name=prod",
+			out: ResourceID{APIVersion: "v1", Kind: "Namespace", Name: "prod"},
+		},
+		{
+			in:  "name=web,kind=Deployment,apiVersion=apps/v1",
+			out: ResourceID{APIVersion: "apps/v1", Kind: "Deployment", Name: "web"},
+		},
+		{
+			in:  "apiVersion=v1,kind=ConfigMap",
+			err: true,
+		},
+		{
+			in:  "apiVersion=v1,kind=ConfigMap,name=",
+			err: true,
+		},
+		{
+			in:  "apiVersion=v1,kind=ConfigMap,name=foo,color=blue",
+			err: true,
+		},
+		{
+			in:  "default/foo",
+			err: true,
+		},
+	}
+
+	for i, s := range samples {
+		t.Run(fmt.Sprintf("sample%d", i+1), func(t *testing.T) {
+			rid, err := ParseResourceID(s.in)
+			if s.err {
+				if err == nil {
+					t.Fatalf("expected error for %q", s.in)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if rid != s.out {
+				t.Fatalf("expected %#v, got %#v", s.out, rid)
+			}
+		})
+	}
+}
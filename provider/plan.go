@@ -2,6 +2,7 @@ package provider
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"github.com/davecgh/go-spew/spew"
@@ -9,13 +10,16 @@ import (
 	"github.com/hashicorp/terraform-plugin-go/tftypes"
 	"github.com/hashicorp/terraform-provider-kubernetes-alpha/morph"
 	"github.com/hashicorp/terraform-provider-kubernetes-alpha/payload"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/dynamic"
 )
 
-func (s *RawProviderServer) dryRun(ctx context.Context, obj tftypes.Value) error {
+func (s *RawProviderServer) dryRun(ctx context.Context, obj tftypes.Value, fieldManager string, forceConflicts bool) error {
 	c, err := s.getDynamicClient()
 	if err != nil {
 		return fmt.Errorf("failed to retrieve Kubernetes dynamic client during apply: %v", err)
@@ -66,7 +70,8 @@ func (s *RawProviderServer) dryRun(ctx context.Context, obj tftypes.Value) error
 	}
 	_, err = rs.Patch(ctx, rname, types.ApplyPatchType, jsonManifest,
 		metav1.PatchOptions{
-			FieldManager: "Terraform",
+			FieldManager: fieldManager,
+			Force:        &forceConflicts,
 			DryRun:       []string{"All"},
 		},
 	)
@@ -74,14 +79,69 @@ func (s *RawProviderServer) dryRun(ctx context.Context, obj tftypes.Value) error
 	return err
 }
 
+// objectFromManifest types a manifest against the OpenAPI schema of its resource and backfills
+// the attributes it doesn't set with unknown values, the same way PlanResourceChange plans 'object'
+func (s *RawProviderServer) objectFromManifest(ctx context.Context, manifest tftypes.Value) (tftypes.Value, error) {
+	rm, err := s.getRestMapper()
+	if err != nil {
+		return tftypes.Value{}, fmt.Errorf("failed to create K8s RESTMapper client: %s", err)
+	}
+	gvk, err := GVKFromTftypesObject(&manifest, rm)
+	if err != nil {
+		return tftypes.Value{}, fmt.Errorf("failed to determine GroupVersionResource for manifest: %s", err)
+	}
+	manifest, err = s.applyManifestDefaults(manifest, gvk, rm)
+	if err != nil {
+		return tftypes.Value{}, err
+	}
+	objectType, err := s.TFTypeFromOpenAPI(ctx, gvk, false)
+	if err != nil {
+		return tftypes.Value{}, fmt.Errorf("failed to determine resource type ID: %s", err)
+	}
+	if !objectType.Is(tftypes.Object{}) {
+		// non-structural resources have no schema
+		objectType = manifest.Type()
+	}
+	mobj, err := morph.ValueToType(manifest, objectType, tftypes.NewAttributePath())
+	if err != nil {
+		return tftypes.Value{}, fmt.Errorf("failed to morph manifest to OAPI type: %s", err)
+	}
+	return morph.DeepUnknown(objectType, mobj, tftypes.NewAttributePath())
+}
+
+// applyManifestDefaults adds the default namespace, labels and annotations of the provider to a manifest,
+// where it doesn't set them. Only the planned 'object' carries them, the 'manifest' attribute stays as configured.
+func (s *RawProviderServer) applyManifestDefaults(manifest tftypes.Value, gvk schema.GroupVersionKind, rm meta.RESTMapper) (tftypes.Value, error) {
+	var err error
+	if s.defaultNamespace != "" {
+		ns, err := IsResourceNamespaced(gvk, rm)
+		if err != nil {
+			return manifest, fmt.Errorf("failed to discover scope of resource %q: %s", gvk.String(), err)
+		}
+		if ns {
+			manifest, err = manifestWithNamespace(manifest, s.defaultNamespace)
+			if err != nil {
+				return manifest, err
+			}
+		}
+	}
+	manifest, err = manifestWithMetadataDefaults(manifest, "labels", s.defaultLabels)
+	if err != nil {
+		return manifest, err
+	}
+	return manifestWithMetadataDefaults(manifest, "annotations", s.defaultAnnotations)
+}
+
 // PlanResourceChange function
 func (s *RawProviderServer) PlanResourceChange(ctx context.Context, req *tfprotov5.PlanResourceChangeRequest) (*tfprotov5.PlanResourceChangeResponse, error) {
 	resp := &tfprotov5.PlanResourceChangeResponse{}
 
 	// test if credentials are valid - we're going to need them further down
-	resp.Diagnostics = append(resp.Diagnostics, s.checkValidCredentials(ctx)...)
-	if len(resp.Diagnostics) > 0 {
-		return resp, nil
+	if !s.clientConfigUnknown {
+		resp.Diagnostics = append(resp.Diagnostics, s.checkValidCredentials(ctx)...)
+		if len(resp.Diagnostics) > 0 {
+			return resp, nil
+		}
 	}
 
 	rt, err := GetResourceType(req.TypeName)
@@ -166,6 +226,25 @@ func (s *RawProviderServer) PlanResourceChange(ctx context.Context, req *tfproto
 		return resp, nil
 	}
 
+	if s.clientConfigUnknown {
+		// without a client the manifest can neither be validated nor typed against the OpenAPI schema,
+		// so the whole object is left unknown and worked out from the manifest during apply
+		s.logger.Debug("[PlanResourceChange]", "provider configuration is not known", "deferring object to apply")
+		proposedVal["object"] = tftypes.NewValue(tftypes.DynamicPseudoType, tftypes.UnknownValue)
+		propStateVal := tftypes.NewValue(proposedState.Type(), proposedVal)
+		plannedState, err := tfprotov5.NewDynamicValue(propStateVal.Type(), propStateVal)
+		if err != nil {
+			resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
+				Severity: tfprotov5.DiagnosticSeverityError,
+				Summary:  "Failed to assemble proposed state during plan",
+				Detail:   err.Error(),
+			})
+			return resp, nil
+		}
+		resp.PlannedState = &plannedState
+		return resp, nil
+	}
+
 	rm, err := s.getRestMapper()
 	if err != nil {
 		resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
@@ -185,6 +264,16 @@ func (s *RawProviderServer) PlanResourceChange(ctx context.Context, req *tfproto
 		return resp, nil
 	}
 
+	ppMan, err = s.applyManifestDefaults(ppMan, gvk, rm)
+	if err != nil {
+		resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
+			Severity: tfprotov5.DiagnosticSeverityError,
+			Summary:  "Failed to apply provider defaults to manifest",
+			Detail:   err.Error(),
+		})
+		return resp, nil
+	}
+
 	vdiags := s.validateResourceOnline(&ppMan)
 	if len(vdiags) > 0 {
 		resp.Diagnostics = append(resp.Diagnostics, vdiags...)
@@ -208,7 +297,23 @@ func (s *RawProviderServer) PlanResourceChange(ctx context.Context, req *tfproto
 			Detail:   "We could not find an OpenAPI schema for this custom resource. Updates to this resource will cause a forced replacement.",
 		})
 
-		err := s.dryRun(ctx, ppMan)
+		fieldManager, forceConflicts, err := s.fieldManager(proposedVal["field_manager"])
+		if err != nil {
+			resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
+				Severity:  tfprotov5.DiagnosticSeverityError,
+				Summary:   "Invalid field_manager block",
+				Detail:    err.Error(),
+				Attribute: tftypes.NewAttributePath().WithAttributeName("field_manager"),
+			})
+			return resp, nil
+		}
+		err = s.dryRun(ctx, ppMan, fieldManager, forceConflicts)
+		if status := apierrors.APIStatus(nil); errors.As(err, &status) {
+			if cdiags := FieldManagerConflictDiagnostics(status.Status(), fieldManager); len(cdiags) > 0 {
+				resp.Diagnostics = append(resp.Diagnostics, cdiags...)
+				return resp, nil
+			}
+		}
 		if err != nil {
 			resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
 				Severity: tfprotov5.DiagnosticSeverityError,
@@ -250,7 +355,27 @@ func (s *RawProviderServer) PlanResourceChange(ctx context.Context, req *tfproto
 	}
 	s.logger.Debug("[PlanResourceChange]", "backfilled manifest", spew.Sdump(completeObj))
 
+	computedFields, err := parseComputedFields(proposedVal["computed_fields"])
+	if err != nil {
+		resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
+			Severity:  tfprotov5.DiagnosticSeverityError,
+			Summary:   "Invalid computed_fields attribute",
+			Detail:    err.Error(),
+			Attribute: tftypes.NewAttributePath().WithAttributeName("computed_fields"),
+		})
+		return resp, nil
+	}
+
 	if proposedVal["object"].IsNull() { // plan for Create
+		completeObj, err = markComputedFields(completeObj, computedFields)
+		if err != nil {
+			resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
+				Severity: tfprotov5.DiagnosticSeverityError,
+				Summary:  "Failed to mark computed fields as unknown",
+				Detail:   err.Error(),
+			})
+			return resp, nil
+		}
 		s.logger.Debug("[PlanResourceChange]", "creating object", spew.Sdump(completeObj))
 		proposedVal["object"] = completeObj
 	} else { // plan for Update
@@ -266,7 +391,31 @@ func (s *RawProviderServer) PlanResourceChange(ctx context.Context, req *tfproto
 			})
 			return resp, nil
 		}
+		var priorMan tftypes.Value
+		if len(computedFields) > 0 {
+			// bring the prior manifest to the same shape as the planned object so that values can be compared
+			priorMan, err = s.applyManifestDefaults(priorVal["manifest"], gvk, rm)
+			if err == nil {
+				priorMan, err = morph.ValueToType(priorMan, objectType, tftypes.NewAttributePath())
+			}
+			if err != nil {
+				s.logger.Debug("[PlanResourceChange]", "failed to morph prior manifest", err.Error())
+				priorMan = tftypes.NewValue(objectType, nil)
+			}
+		}
 		updatedObj, err := tftypes.Transform(completeObj, func(ap *tftypes.AttributePath, v tftypes.Value) (tftypes.Value, error) {
+			if _, ok := computedFields[ap.String()]; ok && v.IsKnown() {
+				// the API may rewrite this value - only plan a change when the configuration changed
+				wasVal, restPath, err := tftypes.WalkAttributePath(priorMan, ap)
+				if err != nil || len(restPath.Steps()) > 0 || !wasVal.(tftypes.Value).Equal(v) {
+					return tftypes.NewValue(v.Type(), tftypes.UnknownValue), nil
+				}
+				nowVal, restPath, err := tftypes.WalkAttributePath(priorObj, ap)
+				if err == nil && len(restPath.Steps()) == 0 {
+					return nowVal.(tftypes.Value), nil
+				}
+				return v, nil
+			}
 			if v.IsKnown() { // this is a value from current configuration - include it in the plan
 				return v, nil
 			}
@@ -298,6 +447,15 @@ func (s *RawProviderServer) PlanResourceChange(ctx context.Context, req *tfproto
 			})
 			return resp, nil
 		}
+		updatedObj, err = planComputedFields(updatedObj, priorObj, computedFields)
+		if err != nil {
+			resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
+				Severity: tfprotov5.DiagnosticSeverityError,
+				Summary:  "Failed to mark computed fields as unknown",
+				Detail:   err.Error(),
+			})
+			return resp, nil
+		}
 
 		proposedVal["object"] = updatedObj
 	}
@@ -317,3 +475,53 @@ func (s *RawProviderServer) PlanResourceChange(ctx context.Context, req *tfproto
 	resp.PlannedState = &plannedState
 	return resp, nil
 }
+
+// markComputedFields sets the values of the computed fields in an object to unknown
+func markComputedFields(obj tftypes.Value, computedFields map[string]*tftypes.AttributePath) (tftypes.Value, error) {
+	if len(computedFields) == 0 {
+		return obj, nil
+	}
+	return tftypes.Transform(obj, func(ap *tftypes.AttributePath, v tftypes.Value) (tftypes.Value, error) {
+		if _, ok := computedFields[ap.String()]; ok {
+			return tftypes.NewValue(v.Type(), tftypes.UnknownValue), nil
+		}
+		return v, nil
+	})
+}
+
+// planComputedFields decides the planned values of computed fields on update. The prior values
+// can only be kept when the object doesn't change at all: any change is applied to the API server,
+// whose admission webhooks may rewrite the computed fields again.
+func planComputedFields(planned tftypes.Value, prior tftypes.Value, computedFields map[string]*tftypes.AttributePath) (tftypes.Value, error) {
+	if len(computedFields) == 0 || planned.Equal(prior) {
+		return planned, nil
+	}
+	return markComputedFields(planned, computedFields)
+}
+
+// parseComputedFields converts the 'computed_fields' attribute into attribute paths of the 'object' attribute,
+// keyed by their string representation
+func parseComputedFields(v tftypes.Value) (map[string]*tftypes.AttributePath, error) {
+	computedFields := make(map[string]*tftypes.AttributePath)
+	if v.IsNull() || !v.IsFullyKnown() {
+		return computedFields, nil
+	}
+	var fields []tftypes.Value
+	err := v.As(&fields)
+	if err != nil {
+		return computedFields, err
+	}
+	for _, f := range fields {
+		var fp string
+		err = f.As(&fp)
+		if err != nil {
+			return computedFields, err
+		}
+		ap, err := FieldPathToTftypesPath(fp)
+		if err != nil {
+			return computedFields, err
+		}
+		computedFields[ap.String()] = ap
+	}
+	return computedFields, nil
+}
@@ -0,0 +1,207 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestPlanResourceChangeUnknownProviderConfig(t *testing.T) {
+	ps := &RawProviderServer{logger: hclog.NewNullLogger()}
+	resp, err := ps.ConfigureProvider(context.Background(), &tfprotov5.ConfigureProviderRequest{
+		Config: testProviderConfig(t, map[string]tftypes.Value{
+			"host":  tftypes.NewValue(tftypes.String, tftypes.UnknownValue),
+			"token": tftypes.NewValue(tftypes.String, "secret"),
+		}),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if errs := testConfigureErrors(resp); len(errs) > 0 {
+		t.Fatalf("unexpected error: %s: %s", errs[0].Summary, errs[0].Detail)
+	}
+	if !ps.clientConfigUnknown || ps.clientConfig != nil {
+		t.Fatal("expected client configuration to be deferred")
+	}
+
+	rt, err := GetResourceType("kubernetes_manifest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	manifest := tftypes.NewValue(tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+		"apiVersion": tftypes.String,
+		"kind":       tftypes.String,
+		"metadata":   tftypes.Object{AttributeTypes: map[string]tftypes.Type{"name": tftypes.String}},
+	}}, map[string]tftypes.Value{
+		"apiVersion": tftypes.NewValue(tftypes.String, "v1"),
+		"kind":       tftypes.NewValue(tftypes.String, "Namespace"),
+		"metadata": tftypes.NewValue(tftypes.Object{AttributeTypes: map[string]tftypes.Type{"name": tftypes.String}}, map[string]tftypes.Value{
+			"name": tftypes.NewValue(tftypes.String, "prod"),
+		}),
+	})
+	vals := make(map[string]tftypes.Value)
+	for k, at := range rt.(tftypes.Object).AttributeTypes {
+		vals[k] = tftypes.NewValue(at, nil)
+	}
+	vals["manifest"] = manifest
+	proposed, err := tfprotov5.NewDynamicValue(rt, tftypes.NewValue(rt, vals))
+	if err != nil {
+		t.Fatal(err)
+	}
+	prior, err := tfprotov5.NewDynamicValue(rt, tftypes.NewValue(rt, nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// no API server is reachable, so this only succeeds if all online steps are skipped
+	presp, err := ps.PlanResourceChange(context.Background(), &tfprotov5.PlanResourceChangeRequest{
+		TypeName:         "kubernetes_manifest",
+		ProposedNewState: &proposed,
+		PriorState:       &prior,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(presp.Diagnostics) > 0 {
+		t.Fatalf("unexpected diagnostic: %s: %s", presp.Diagnostics[0].Summary, presp.Diagnostics[0].Detail)
+	}
+	planned, err := presp.PlannedState.Unmarshal(rt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var plannedVals map[string]tftypes.Value
+	err = planned.As(&plannedVals)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if plannedVals["object"].IsKnown() {
+		t.Fatalf("expected planned object to be unknown, got %s", plannedVals["object"])
+	}
+	if !plannedVals["manifest"].Equal(manifest) {
+		t.Fatalf("expected planned manifest to be unchanged, got %s", plannedVals["manifest"])
+	}
+
+	if diags := ps.checkValidCredentials(context.Background()); len(diags) == 0 {
+		t.Fatal("expected online operations to be rejected while the configuration is unknown")
+	}
+}
+
+func TestParseComputedFields(t *testing.T) {
+	samples := []struct {
+		in  []string
+		out []string
+		err bool
+	}{
+		{
+			in:  []string{"metadata.annotations", `metadata.labels["app.kubernetes.io/name"]`, "spec.containers[1].image"},
+			out: []string{"metadata.annotations", `metadata.labels["app.kubernetes.io/name"]`, "spec.containers[1].image"},
+		},
+		{
+			in:  []string{},
+			out: []string{},
+		},
+		{
+			in:  []string{"spec.containers[*].image"},
+			err: true,
+		},
+		{
+			in:  []string{"spec..image"},
+			err: true,
+		},
+	}
+
+	for i, s := range samples {
+		t.Run(fmt.Sprintf("sample%d", i+1), func(t *testing.T) {
+			var fields []tftypes.Value
+			for _, f := range s.in {
+				fields = append(fields, tftypes.NewValue(tftypes.String, f))
+			}
+			v := tftypes.NewValue(tftypes.List{ElementType: tftypes.String}, fields)
+			cf, err := parseComputedFields(v)
+			if s.err {
+				if err == nil {
+					t.Fatalf("expected error for %v", s.in)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if len(cf) != len(s.out) {
+				t.Fatalf("expected %d paths, got %d", len(s.out), len(cf))
+			}
+			for _, o := range s.out {
+				ap, _ := FieldPathToTftypesPath(o)
+				if _, ok := cf[ap.String()]; !ok {
+					t.Fatalf("expected path %q in %v", o, cf)
+				}
+			}
+		})
+	}
+
+	cf, err := parseComputedFields(tftypes.NewValue(tftypes.List{ElementType: tftypes.String}, nil))
+	if err != nil || len(cf) != 0 {
+		t.Fatalf("expected no paths for null value, got %v, %v", cf, err)
+	}
+}
+
+func TestPlanComputedFields(t *testing.T) {
+	specType := tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+		"replicas": tftypes.Number,
+		"caBundle": tftypes.String,
+	}}
+	objType := tftypes.Object{AttributeTypes: map[string]tftypes.Type{"spec": specType}}
+	obj := func(replicas int, caBundle interface{}) tftypes.Value {
+		return tftypes.NewValue(objType, map[string]tftypes.Value{
+			"spec": tftypes.NewValue(specType, map[string]tftypes.Value{
+				"replicas": tftypes.NewValue(tftypes.Number, replicas),
+				"caBundle": tftypes.NewValue(tftypes.String, caBundle),
+			}),
+		})
+	}
+	caBundle, _ := FieldPathToTftypesPath("spec.caBundle")
+	computed := map[string]*tftypes.AttributePath{caBundle.String(): caBundle}
+
+	samples := []struct {
+		planned  tftypes.Value
+		prior    tftypes.Value
+		computed map[string]*tftypes.AttributePath
+		out      tftypes.Value
+	}{
+		{
+			// nothing changed, the prior value of the computed field is kept
+			planned:  obj(1, "Y2EK"),
+			prior:    obj(1, "Y2EK"),
+			computed: computed,
+			out:      obj(1, "Y2EK"),
+		},
+		{
+			// other field changed, computed field unchanged
+			planned:  obj(2, "Y2EK"),
+			prior:    obj(1, "Y2EK"),
+			computed: computed,
+			out:      obj(2, tftypes.UnknownValue),
+		},
+		{
+			planned: obj(2, "Y2EK"),
+			prior:   obj(1, "Y2EK"),
+			out:     obj(2, "Y2EK"),
+		},
+	}
+
+	for i, s := range samples {
+		t.Run(fmt.Sprintf("sample%d", i+1), func(t *testing.T) {
+			out, err := planComputedFields(s.planned, s.prior, s.computed)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if !out.Equal(s.out) {
+				t.Fatalf("expected %s, got %s", s.out, out)
+			}
+		})
+	}
+}
@@ -9,14 +9,83 @@ import (
 
 // GetObjectTypeFromSchema returns a tftypes.Type that can wholy represent the schema input
 func GetObjectTypeFromSchema(schema *tfprotov5.Schema) tftypes.Type {
+	return getObjectTypeFromSchemaBlock(schema.Block)
+}
+
+// getObjectTypeFromSchemaBlock returns the tftypes.Type of a schema block, including its nested blocks
+func getObjectTypeFromSchemaBlock(b *tfprotov5.SchemaBlock) tftypes.Type {
 	bm := map[string]tftypes.Type{}
 
-	for _, att := range schema.Block.Attributes {
+	for _, att := range b.Attributes {
 		bm[att.Name] = att.Type
 	}
+	for _, nb := range b.BlockTypes {
+		bt := getObjectTypeFromSchemaBlock(nb.Block)
+		switch nb.Nesting {
+		case tfprotov5.SchemaNestedBlockNestingModeSingle, tfprotov5.SchemaNestedBlockNestingModeGroup:
+			bm[nb.TypeName] = bt
+		case tfprotov5.SchemaNestedBlockNestingModeSet:
+			bm[nb.TypeName] = tftypes.Set{ElementType: bt}
+		case tfprotov5.SchemaNestedBlockNestingModeMap:
+			bm[nb.TypeName] = tftypes.Map{AttributeType: bt}
+		default:
+			bm[nb.TypeName] = tftypes.List{ElementType: bt}
+		}
+	}
 	return tftypes.Object{AttributeTypes: bm}
 }
 
+// fieldManagerBlock is the 'field_manager' block of both the provider and the 'kubernetes_manifest' resource
+func fieldManagerBlock(nameDescription string) *tfprotov5.SchemaNestedBlock {
+	return &tfprotov5.SchemaNestedBlock{
+		TypeName: "field_manager",
+		Nesting:  tfprotov5.SchemaNestedBlockNestingModeList,
+		MaxItems: 1,
+		Block: &tfprotov5.SchemaBlock{
+			Description: "Configure the field manager used for server-side apply.",
+			Attributes: []*tfprotov5.SchemaAttribute{
+				{
+					Name:        "name",
+					Type:        tftypes.String,
+					Optional:    true,
+					Description: nameDescription,
+				},
+				{
+					Name:        "force_conflicts",
+					Type:        tftypes.Bool,
+					Optional:    true,
+					Description: "Take ownership of fields owned by other field managers instead of failing with a conflict.",
+				},
+			},
+		},
+	}
+}
+
+// timeoutsBlock is the 'timeouts' block of both the provider and the 'kubernetes_manifest' resource
+func timeoutsBlock(description string) *tfprotov5.SchemaNestedBlock {
+	atts := []*tfprotov5.SchemaAttribute{}
+	for _, op := range timeoutOperations {
+		desc := fmt.Sprintf("How long to wait for the resource to be %sd", op)
+		if op != "delete" {
+			desc += ", including the conditions in `wait_for`"
+		}
+		atts = append(atts, &tfprotov5.SchemaAttribute{
+			Name:        op,
+			Type:        tftypes.String,
+			Optional:    true,
+			Description: desc + ", as a duration string like `\"10m\"`.",
+		})
+	}
+	return &tfprotov5.SchemaNestedBlock{
+		TypeName: "timeouts",
+		Nesting:  tfprotov5.SchemaNestedBlockNestingModeSingle,
+		Block: &tfprotov5.SchemaBlock{
+			Description: description,
+			Attributes:  atts,
+		},
+	}
+}
+
 // GetResourceType returns the tftypes.Type of a resource of type 'name'
 func GetResourceType(name string) (tftypes.Type, error) {
 	sch := GetProviderResourceSchema()
@@ -29,17 +98,9 @@ func GetResourceType(name string) (tftypes.Type, error) {
 
 // GetProviderResourceSchema contains the definitions of all supported resources
 func GetProviderResourceSchema() map[string]*tfprotov5.Schema {
-	waitForType := tftypes.Object{
-		AttributeTypes: map[string]tftypes.Type{
-			"fields": tftypes.Map{
-				AttributeType: tftypes.String,
-			},
-		},
-	}
-
 	return map[string]*tfprotov5.Schema{
 		"kubernetes_manifest": {
-			Version: 1,
+			Version: 2,
 			Block: &tfprotov5.SchemaBlock{
 				Attributes: []*tfprotov5.SchemaAttribute{
 					{
@@ -57,9 +118,229 @@ func GetProviderResourceSchema() map[string]*tfprotov5.Schema {
 					},
 					{
 						Name:        "wait_for",
-						Type:        waitForType,
+						Type:        tftypes.DynamicPseudoType,
+						Optional:    true,
+						Description: "Conditions to wait for after each apply. `fields` is a map of attribute paths and desired patterns to be matched. `condition` is an object, or a list of objects, with the `type`, `status` and optional `reason` of entries in `status.conditions` to be matched. `expression` is a JSONPath template, optionally compared to another template or a JSON value, that has to hold for the resource. `ready` enables the built-in readiness check for the kind of the resource.",
+					},
+					{
+						Name:        "computed_fields",
+						Type:        tftypes.List{ElementType: tftypes.String},
+						Optional:    true,
+						Description: "A list of attribute paths in `object` whose values are set or rewritten by the API server, for example by a mutating admission webhook. Their values are unknown during plan and taken from the API response on apply.",
+					},
+				},
+				BlockTypes: []*tfprotov5.SchemaNestedBlock{
+					fieldManagerBlock("The name of the field manager. Defaults to the field manager configured on the provider."),
+					timeoutsBlock("Timeouts for creating, updating and deleting the resource. Defaults to the timeouts configured on the provider."),
+				},
+			},
+		},
+	}
+}
+
+// GetDataSourceType returns the tftypes.Type of a data source of type 'name'
+func GetDataSourceType(name string) (tftypes.Type, error) {
+	sch := GetProviderDataSourceSchema()
+	rsch, ok := sch[name]
+	if !ok {
+		return tftypes.DynamicPseudoType, fmt.Errorf("unknown data source %s - cannot find schema", name)
+	}
+	return GetObjectTypeFromSchema(rsch), nil
+}
+
+// GetProviderDataSourceSchema contains the definitions of all supported data sources
+func GetProviderDataSourceSchema() map[string]*tfprotov5.Schema {
+	serverVersionType := tftypes.Object{
+		AttributeTypes: map[string]tftypes.Type{
+			"major":       tftypes.String,
+			"minor":       tftypes.String,
+			"git_version": tftypes.String,
+			"git_commit":  tftypes.String,
+			"build_date":  tftypes.String,
+			"platform":    tftypes.String,
+		},
+	}
+	apiGroupType := tftypes.Object{
+		AttributeTypes: map[string]tftypes.Type{
+			"preferred_version": tftypes.String,
+			"versions":          tftypes.List{ElementType: tftypes.String},
+		},
+	}
+	apiResourceType := tftypes.Object{
+		AttributeTypes: map[string]tftypes.Type{
+			"name":          tftypes.String,
+			"kind":          tftypes.String,
+			"group_version": tftypes.String,
+			"scope":         tftypes.String,
+			"verbs":         tftypes.List{ElementType: tftypes.String},
+			"short_names":   tftypes.List{ElementType: tftypes.String},
+		},
+	}
+
+	return map[string]*tfprotov5.Schema{
+		"kubernetes_resource": {
+			Version: 1,
+			Block: &tfprotov5.SchemaBlock{
+				Attributes: []*tfprotov5.SchemaAttribute{
+					{
+						Name:        "api_version",
+						Type:        tftypes.String,
+						Required:    true,
+						Description: "The API version of the requested resource.",
+					},
+					{
+						Name:        "kind",
+						Type:        tftypes.String,
+						Required:    true,
+						Description: "The Kind of the requested resource.",
+					},
+					{
+						Name:        "namespace",
+						Type:        tftypes.String,
+						Optional:    true,
+						Description: "The namespace of the requested resource. Required for namespaced resources.",
+					},
+					{
+						Name:        "name",
+						Type:        tftypes.String,
+						Required:    true,
+						Description: "The name of the requested resource.",
+					},
+					{
+						Name:        "object",
+						Type:        tftypes.DynamicPseudoType,
+						Computed:    true,
+						Description: "The response from the API server, including the 'status' attribute. Null if the resource does not exist.",
+					},
+				},
+			},
+		},
+		"kubernetes_resources": {
+			Version: 1,
+			Block: &tfprotov5.SchemaBlock{
+				Attributes: []*tfprotov5.SchemaAttribute{
+					{
+						Name:        "api_version",
+						Type:        tftypes.String,
+						Required:    true,
+						Description: "The API version of the requested resources.",
+					},
+					{
+						Name:        "kind",
+						Type:        tftypes.String,
+						Required:    true,
+						Description: "The Kind of the requested resources.",
+					},
+					{
+						Name:        "namespace",
+						Type:        tftypes.String,
 						Optional:    true,
-						Description: "A map of attribute paths and desired patterns to be matched. After each apply the provider will wait for all attributes listed here to reach a value that matches the desired pattern.",
+						Description: "The namespace of the requested resources. Resources from all namespaces are returned if not set.",
+					},
+					{
+						Name:        "label_selector",
+						Type:        tftypes.String,
+						Optional:    true,
+						Description: "A selector to restrict the list of returned resources by their labels, e.g. 'team=payments,tier!=frontend'.",
+					},
+					{
+						Name:        "field_selector",
+						Type:        tftypes.String,
+						Optional:    true,
+						Description: "A selector to restrict the list of returned resources by their fields, e.g. 'status.phase=Running'.",
+					},
+					{
+						Name:        "limit",
+						Type:        tftypes.Number,
+						Optional:    true,
+						Description: "The maximum number of resources to return. All matching resources are returned if not set.",
+					},
+					{
+						Name:        "objects",
+						Type:        tftypes.DynamicPseudoType,
+						Computed:    true,
+						Description: "The list of resources returned by the API server, including their 'status' attribute.",
+					},
+				},
+			},
+		},
+		"kubernetes_discovery": {
+			Version: 1,
+			Block: &tfprotov5.SchemaBlock{
+				Attributes: []*tfprotov5.SchemaAttribute{
+					{
+						Name:        "server_version",
+						Type:        serverVersionType,
+						Computed:    true,
+						Description: "Version information reported by the API server.",
+					},
+					{
+						Name:        "api_versions",
+						Type:        tftypes.List{ElementType: tftypes.String},
+						Computed:    true,
+						Description: "All API versions served by the cluster, in 'group/version' form (just 'version' for the core group).",
+					},
+					{
+						Name:        "api_groups",
+						Type:        tftypes.Map{AttributeType: apiGroupType},
+						Computed:    true,
+						Description: "The API groups served by the cluster, keyed by group name. The core group has an empty name.",
+					},
+					{
+						Name:        "resources",
+						Type:        tftypes.List{ElementType: apiResourceType},
+						Computed:    true,
+						Description: "The API resources served by the cluster, excluding subresources.",
+					},
+				},
+			},
+		},
+		"kubernetes_schema": {
+			Version: 1,
+			Block: &tfprotov5.SchemaBlock{
+				Attributes: []*tfprotov5.SchemaAttribute{
+					{
+						Name:        "api_version",
+						Type:        tftypes.String,
+						Required:    true,
+						Description: "The API version of the resource type.",
+					},
+					{
+						Name:        "kind",
+						Type:        tftypes.String,
+						Required:    true,
+						Description: "The Kind of the resource type.",
+					},
+					{
+						Name:        "include_status",
+						Type:        tftypes.Bool,
+						Optional:    true,
+						Description: "Include the 'status' attribute in the type. The type used for 'manifest' never includes it (default false).",
+					},
+					{
+						Name:        "type_json",
+						Type:        tftypes.String,
+						Computed:    true,
+						Description: "The Terraform type derived from the OpenAPI schema of the resource type, in JSON type constraint notation.",
+					},
+				},
+			},
+		},
+		"kubernetes_manifest_decode": {
+			Version: 1,
+			Block: &tfprotov5.SchemaBlock{
+				Attributes: []*tfprotov5.SchemaAttribute{
+					{
+						Name:        "content",
+						Type:        tftypes.String,
+						Required:    true,
+						Description: "One or more YAML or JSON documents, separated by '---'.",
+					},
+					{
+						Name:        "manifests",
+						Type:        tftypes.DynamicPseudoType,
+						Computed:    true,
+						Description: "The decoded manifests, keyed by 'kind/namespace/name' ('kind/name' for cluster level resources). Documents of kind 'List' are expanded into their items.",
 					},
 				},
 			},
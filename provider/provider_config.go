@@ -75,6 +75,50 @@ func GetProviderConfigSchema() *tfprotov5.Schema {
 				DescriptionKind: 0,
 				Deprecated:      false,
 			},
+			{
+				Name:            "client_certificate_file",
+				Type:            tftypes.String,
+				Description:     "Path to a PEM-encoded client TLS certificate.",
+				Required:        false,
+				Optional:        true,
+				Computed:        false,
+				Sensitive:       false,
+				DescriptionKind: 0,
+				Deprecated:      false,
+			},
+			{
+				Name:            "client_key_file",
+				Type:            tftypes.String,
+				Description:     "Path to a PEM-encoded private key for the client certificate.",
+				Required:        false,
+				Optional:        true,
+				Computed:        false,
+				Sensitive:       false,
+				DescriptionKind: 0,
+				Deprecated:      false,
+			},
+			{
+				Name:            "cluster_ca_certificate_file",
+				Type:            tftypes.String,
+				Description:     "Path to a PEM-encoded CA TLS certificate.",
+				Required:        false,
+				Optional:        true,
+				Computed:        false,
+				Sensitive:       false,
+				DescriptionKind: 0,
+				Deprecated:      false,
+			},
+			{
+				Name:            "tls_server_name",
+				Type:            tftypes.String,
+				Description:     "Server name used to verify the API server certificate, instead of the hostname in 'host'.",
+				Required:        false,
+				Optional:        true,
+				Computed:        false,
+				Sensitive:       false,
+				DescriptionKind: 0,
+				Deprecated:      false,
+			},
 			{
 				Name:            "config_path",
 				Type:            tftypes.String,
@@ -86,6 +130,17 @@ func GetProviderConfigSchema() *tfprotov5.Schema {
 				DescriptionKind: 0,
 				Deprecated:      false,
 			},
+			{
+				Name:            "config_paths",
+				Type:            tftypes.List{ElementType: tftypes.String},
+				Description:     "A list of paths to kubeconfig files, merged the same way kubectl merges the files in KUBECONFIG.",
+				Required:        false,
+				Optional:        true,
+				Computed:        false,
+				Sensitive:       false,
+				DescriptionKind: 0,
+				Deprecated:      false,
+			},
 			{
 				Name:            "config_context",
 				Type:            tftypes.String,
@@ -142,16 +197,122 @@ func GetProviderConfigSchema() *tfprotov5.Schema {
 				Deprecated:      false,
 			},
 			{
-				Name: "exec",
-				Type: tftypes.Object{
-					AttributeTypes: map[string]tftypes.Type{
-						"api_version": tftypes.String,
-						"command":     tftypes.String,
-						"env":         tftypes.Map{AttributeType: tftypes.String},
-						"args":        tftypes.List{ElementType: tftypes.String},
-					},
-				},
-				Description:     "",
+				Name:            "proxy_url",
+				Type:            tftypes.String,
+				Description:     "URL to the proxy to be used for all API requests.",
+				Required:        false,
+				Optional:        true,
+				Computed:        false,
+				Sensitive:       false,
+				DescriptionKind: 0,
+				Deprecated:      false,
+			},
+			{
+				Name:            "qps",
+				Type:            tftypes.Number,
+				Description:     "Maximum number of queries per second to the API server, per API client.",
+				Required:        false,
+				Optional:        true,
+				Computed:        false,
+				Sensitive:       false,
+				DescriptionKind: 0,
+				Deprecated:      false,
+			},
+			{
+				Name:            "burst",
+				Type:            tftypes.Number,
+				Description:     "Maximum burst of requests to the API server above 'qps', per API client.",
+				Required:        false,
+				Optional:        true,
+				Computed:        false,
+				Sensitive:       false,
+				DescriptionKind: 0,
+				Deprecated:      false,
+			},
+			{
+				Name:            "request_timeout",
+				Type:            tftypes.String,
+				Description:     "Timeout for a single request to the API server, as a duration string like \"30s\".",
+				Required:        false,
+				Optional:        true,
+				Computed:        false,
+				Sensitive:       false,
+				DescriptionKind: 0,
+				Deprecated:      false,
+			},
+			{
+				Name:            "wait_poll_interval",
+				Type:            tftypes.String,
+				Description:     "Interval between two reads of a resource in `wait_for`, as a duration string like \"5s\". Only used when the API server doesn't support watching resources.",
+				Required:        false,
+				Optional:        true,
+				Computed:        false,
+				Sensitive:       false,
+				DescriptionKind: 0,
+				Deprecated:      false,
+			},
+			{
+				// an object with the attributes: api_version, command, env, args, install_hint,
+				// provide_cluster_info, interactive_mode and cluster_info_extension.
+				// Object types in the schema would make all of them required.
+				Name:            "exec",
+				Type:            tftypes.DynamicPseudoType,
+				Description:     "Exec-based authentication plugin.",
+				Required:        false,
+				Optional:        true,
+				Computed:        false,
+				Sensitive:       false,
+				DescriptionKind: 0,
+				Deprecated:      false,
+			},
+			{
+				Name:            "default_labels",
+				Type:            tftypes.Map{AttributeType: tftypes.String},
+				Description:     "Labels added to the metadata of every manifest. Labels set in the manifest take precedence.",
+				Required:        false,
+				Optional:        true,
+				Computed:        false,
+				Sensitive:       false,
+				DescriptionKind: 0,
+				Deprecated:      false,
+			},
+			{
+				Name:            "default_annotations",
+				Type:            tftypes.Map{AttributeType: tftypes.String},
+				Description:     "Annotations added to the metadata of every manifest. Annotations set in the manifest take precedence.",
+				Required:        false,
+				Optional:        true,
+				Computed:        false,
+				Sensitive:       false,
+				DescriptionKind: 0,
+				Deprecated:      false,
+			},
+			{
+				Name:            "ignore_labels",
+				Type:            tftypes.List{ElementType: tftypes.String},
+				Description:     "Regular expressions matching labels which are left out of the 'object' attribute, unless they are set in the manifest.",
+				Required:        false,
+				Optional:        true,
+				Computed:        false,
+				Sensitive:       false,
+				DescriptionKind: 0,
+				Deprecated:      false,
+			},
+			{
+				Name:            "ignore_annotations",
+				Type:            tftypes.List{ElementType: tftypes.String},
+				Description:     "Regular expressions matching annotations which are left out of the 'object' attribute, unless they are set in the manifest.",
+				Required:        false,
+				Optional:        true,
+				Computed:        false,
+				Sensitive:       false,
+				DescriptionKind: 0,
+				Deprecated:      false,
+			},
+			{
+				Name:            "default_namespace",
+				Type:            tftypes.String,
+				Description:     "Namespace set on manifests of namespaced resources that don't specify one.",
 				Required:        false,
 				Optional:        true,
 				Computed:        false,
@@ -162,6 +323,12 @@ func GetProviderConfigSchema() *tfprotov5.Schema {
 		},
 	}
 
+	b.BlockTypes = []*tfprotov5.SchemaNestedBlock{
+		fieldManagerBlock("The name of the field manager used by all resources which don't configure their own. Defaults to \"Terraform\"."),
+		timeoutsBlock("Default timeouts for resources which don't configure their own."),
+		impersonateBlock(),
+	}
+
 	return &tfprotov5.Schema{
 		Version: 1,
 		Block:   &b,
@@ -170,11 +337,5 @@ func GetProviderConfigSchema() *tfprotov5.Schema {
 
 // GetTypeFromSchema returns the equivalent tftypes.Type representation of a given tfprotov5.Schema
 func GetTypeFromSchema(s *tfprotov5.Schema) tftypes.Type {
-	schemaTypeAttributes := map[string]tftypes.Type{}
-	for _, att := range s.Block.Attributes {
-		schemaTypeAttributes[att.Name] = att.Type
-	}
-	return tftypes.Object{
-		AttributeTypes: schemaTypeAttributes,
-	}
+	return getObjectTypeFromSchemaBlock(s.Block)
 }
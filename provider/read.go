@@ -56,6 +56,12 @@ func (s *RawProviderServer) ReadResource(ctx context.Context, req *tfprotov5.Rea
 		return resp, nil
 	}
 
+	if s.clientConfigUnknown {
+		// the API server cannot be reached until apply, keep the state as it is
+		resp.NewState = req.CurrentState
+		return resp, nil
+	}
+
 	co, hasOb := resState["object"]
 	if !hasOb || co.IsNull() {
 		resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
@@ -139,6 +145,7 @@ func (s *RawProviderServer) ReadResource(ctx context.Context, req *tfprotov5.Rea
 	}
 
 	fo := RemoveServerSideFields(ro.Object)
+	s.removeIgnoredMetadata(fo, resState["manifest"])
 	nobj, err := payload.ToTFValue(fo, objectType, tftypes.NewAttributePath())
 	if err != nil {
 		return resp, err
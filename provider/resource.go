@@ -5,6 +5,8 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"regexp"
+	"sort"
 
 	"github.com/hashicorp/terraform-plugin-go/tftypes"
 	"github.com/hashicorp/terraform-provider-kubernetes-alpha/openapi"
@@ -48,6 +50,12 @@ func GVKFromTftypesObject(in *tftypes.Value, m meta.RESTMapper) (schema.GroupVer
 	if err != nil {
 		return schema.GroupVersionKind{}, err
 	}
+	return GVKFromAPIVersionKind(apv, kind, m)
+}
+
+// GVKFromAPIVersionKind resolves an apiVersion and kind pair into a canonical schema.GroupVersionKind
+// by checking it against the discovery API via a RESTMapper
+func GVKFromAPIVersionKind(apv string, kind string, m meta.RESTMapper) (schema.GroupVersionKind, error) {
 	gv, err := schema.ParseGroupVersion(apv)
 	if err != nil {
 		return schema.GroupVersionKind{}, err
@@ -112,12 +120,12 @@ func (ps *RawProviderServer) TFTypeFromOpenAPI(ctx context.Context, gvk schema.G
 			return nil, fmt.Errorf("cannot get resource type from OpenAPI (%s): %s", gvk.String(), err)
 		}
 	}
-	// remove "status" attribute from resource type
-	if tsch.Is(tftypes.Object{}) && !status {
+	if tsch.Is(tftypes.Object{}) {
 		ot := tsch.(tftypes.Object)
 		atts := make(map[string]tftypes.Type)
 		for k, t := range ot.AttributeTypes {
-			if k != "status" {
+			// remove "status" attribute from resource type, unless requested
+			if k != "status" || status {
 				atts[k] = t
 			}
 		}
@@ -143,6 +151,177 @@ func (ps *RawProviderServer) TFTypeFromOpenAPI(ctx context.Context, gvk schema.G
 	return tsch, nil
 }
 
+// manifestWithNamespace returns a copy of a manifest with 'metadata.namespace' set,
+// unless the manifest already sets a namespace
+func manifestWithNamespace(manifest tftypes.Value, namespace string) (tftypes.Value, error) {
+	md, mdObj, err := manifestMetadata(manifest)
+	if err != nil || mdObj == nil {
+		return manifest, err
+	}
+	if ns, ok := mdObj["namespace"]; ok && !ns.IsNull() {
+		return manifest, nil
+	}
+	md, err = withAttribute(md, "namespace", tftypes.NewValue(tftypes.String, namespace))
+	if err != nil {
+		return manifest, fmt.Errorf("cannot set namespace in 'metadata': %s", err)
+	}
+	return withAttribute(manifest, "metadata", md)
+}
+
+// manifestWithMetadataDefaults returns a copy of a manifest with entries added to one of the
+// string maps in its metadata, like 'labels' or 'annotations'. Entries set by the manifest take precedence.
+func manifestWithMetadataDefaults(manifest tftypes.Value, key string, defaults map[string]string) (tftypes.Value, error) {
+	if len(defaults) == 0 {
+		return manifest, nil
+	}
+	md, mdObj, err := manifestMetadata(manifest)
+	if err != nil || mdObj == nil {
+		return manifest, err
+	}
+	m, ok := mdObj[key]
+	if !ok || m.IsNull() {
+		m = tftypes.NewValue(tftypes.Map{AttributeType: tftypes.String}, map[string]tftypes.Value{})
+	}
+	if !m.IsKnown() {
+		return manifest, nil
+	}
+	var entries map[string]tftypes.Value
+	err = m.As(&entries)
+	if err != nil {
+		return manifest, err
+	}
+	keys := make([]string, 0, len(defaults))
+	for k := range defaults {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		if _, ok := entries[k]; ok {
+			continue
+		}
+		m, err = withAttribute(m, k, tftypes.NewValue(tftypes.String, defaults[k]))
+		if err != nil {
+			return manifest, fmt.Errorf("cannot set default in 'metadata.%s': %s", key, err)
+		}
+	}
+	md, err = withAttribute(md, key, m)
+	if err != nil {
+		return manifest, fmt.Errorf("cannot set '%s' in 'metadata': %s", key, err)
+	}
+	return withAttribute(manifest, "metadata", md)
+}
+
+// manifestMetadata returns the 'metadata' of a manifest and its attributes,
+// or no attributes if the manifest or its metadata are not known
+func manifestMetadata(manifest tftypes.Value) (tftypes.Value, map[string]tftypes.Value, error) {
+	if !manifest.Type().Is(tftypes.Object{}) || manifest.IsNull() || !manifest.IsKnown() {
+		return tftypes.Value{}, nil, nil
+	}
+	var mObj map[string]tftypes.Value
+	err := manifest.As(&mObj)
+	if err != nil {
+		return tftypes.Value{}, nil, err
+	}
+	md, ok := mObj["metadata"]
+	if !ok || md.IsNull() || !md.IsKnown() {
+		return tftypes.Value{}, nil, nil
+	}
+	var mdObj map[string]tftypes.Value
+	err = md.As(&mdObj)
+	if err != nil {
+		return tftypes.Value{}, nil, err
+	}
+	return md, mdObj, nil
+}
+
+// withAttribute returns a copy of an object or map value with an attribute or element set
+func withAttribute(v tftypes.Value, name string, av tftypes.Value) (tftypes.Value, error) {
+	vals := make(map[string]tftypes.Value)
+	if !v.IsNull() {
+		err := v.As(&vals)
+		if err != nil {
+			return v, err
+		}
+	}
+	vals[name] = av
+	switch t := v.Type().(type) {
+	case tftypes.Object:
+		atts := make(map[string]tftypes.Type, len(t.AttributeTypes)+1)
+		for k, at := range t.AttributeTypes {
+			atts[k] = at
+		}
+		atts[name] = av.Type()
+		return tftypes.NewValue(tftypes.Object{AttributeTypes: atts}, vals), nil
+	case tftypes.Map:
+		if !av.Type().Is(t.AttributeType) {
+			return v, fmt.Errorf("cannot add a %s to a map of %s", av.Type(), t.AttributeType)
+		}
+		return tftypes.NewValue(t, vals), nil
+	}
+	return v, fmt.Errorf("unexpected type %s", v.Type())
+}
+
+// removeIgnoredMetadata deletes the labels and annotations matching the ignore rules of the provider from an
+// object returned by the API server, except for those set in the manifest or by the provider defaults
+func (ps *RawProviderServer) removeIgnoredMetadata(obj map[string]interface{}, manifest tftypes.Value) {
+	meta, ok := obj["metadata"].(map[string]interface{})
+	if !ok {
+		return
+	}
+	for _, r := range []struct {
+		key      string
+		ignore   []*regexp.Regexp
+		defaults map[string]string
+	}{
+		{key: "labels", ignore: ps.ignoreLabels, defaults: ps.defaultLabels},
+		{key: "annotations", ignore: ps.ignoreAnnotations, defaults: ps.defaultAnnotations},
+	} {
+		entries, ok := meta[r.key].(map[string]interface{})
+		if !ok || len(r.ignore) == 0 {
+			continue
+		}
+		keep := manifestMetadataKeys(manifest, r.key)
+		for k := range r.defaults {
+			keep[k] = true
+		}
+		for k := range entries {
+			if keep[k] {
+				continue
+			}
+			for _, re := range r.ignore {
+				if re.MatchString(k) {
+					delete(entries, k)
+					break
+				}
+			}
+		}
+		if len(entries) == 0 {
+			delete(meta, r.key)
+		}
+	}
+}
+
+// manifestMetadataKeys returns the keys of one of the string maps in the metadata of a manifest, like 'labels'
+func manifestMetadataKeys(manifest tftypes.Value, key string) map[string]bool {
+	keys := make(map[string]bool)
+	_, mdObj, err := manifestMetadata(manifest)
+	if err != nil || mdObj == nil {
+		return keys
+	}
+	m, ok := mdObj[key]
+	if !ok || m.IsNull() || !m.IsKnown() {
+		return keys
+	}
+	var entries map[string]tftypes.Value
+	if m.As(&entries) != nil {
+		return keys
+	}
+	for k := range entries {
+		keys[k] = true
+	}
+	return keys
+}
+
 func mapRemoveNulls(in map[string]interface{}) map[string]interface{} {
 	for k, v := range in {
 		switch tv := v.(type) {
@@ -3,7 +3,10 @@ package provider
 import (
 	"fmt"
 	"reflect"
+	"regexp"
 	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
 )
 
 func TestRemoveNulls(t *testing.T) {
@@ -66,3 +69,235 @@ func TestRemoveNulls(t *testing.T) {
 		})
 	}
 }
+
+func TestManifestWithNamespace(t *testing.T) {
+	str := func(s string) tftypes.Value {
+		return tftypes.NewValue(tftypes.String, s)
+	}
+	object := func(atts map[string]tftypes.Value) tftypes.Value {
+		types := make(map[string]tftypes.Type)
+		for k, v := range atts {
+			types[k] = v.Type()
+		}
+		return tftypes.NewValue(tftypes.Object{AttributeTypes: types}, atts)
+	}
+	manifest := func(metadata tftypes.Value) tftypes.Value {
+		return object(map[string]tftypes.Value{
+			"apiVersion": str("v1"),
+			"kind":       str("ConfigMap"),
+			"metadata":   metadata,
+		})
+	}
+	stringMap := func(vals map[string]tftypes.Value) tftypes.Value {
+		return tftypes.NewValue(tftypes.Map{AttributeType: tftypes.String}, vals)
+	}
+
+	samples := []struct {
+		in  tftypes.Value
+		out tftypes.Value
+		err bool
+	}{
+		{
+			in:  manifest(object(map[string]tftypes.Value{"name": str("foo")})),
+			out: manifest(object(map[string]tftypes.Value{"name": str("foo"), "namespace": str("prod")})),
+		},
+		{
+			in:  manifest(object(map[string]tftypes.Value{"name": str("foo"), "namespace": str("dev")})),
+			out: manifest(object(map[string]tftypes.Value{"name": str("foo"), "namespace": str("dev")})),
+		},
+		{
+			in:  manifest(object(map[string]tftypes.Value{"name": str("foo"), "namespace": tftypes.NewValue(tftypes.String, nil)})),
+			out: manifest(object(map[string]tftypes.Value{"name": str("foo"), "namespace": str("prod")})),
+		},
+		{
+			// the namespace isn't known yet, it will be set by the configuration
+			in:  manifest(object(map[string]tftypes.Value{"name": str("foo"), "namespace": tftypes.NewValue(tftypes.String, tftypes.UnknownValue)})),
+			out: manifest(object(map[string]tftypes.Value{"name": str("foo"), "namespace": tftypes.NewValue(tftypes.String, tftypes.UnknownValue)})),
+		},
+		{
+			in:  manifest(stringMap(map[string]tftypes.Value{"name": str("foo")})),
+			out: manifest(stringMap(map[string]tftypes.Value{"name": str("foo"), "namespace": str("prod")})),
+		},
+		{
+			in:  manifest(str("foo")),
+			err: true,
+		},
+	}
+
+	for i, s := range samples {
+		t.Run(fmt.Sprintf("sample%d", i+1), func(t *testing.T) {
+			out, err := manifestWithNamespace(s.in, "prod")
+			if s.err {
+				if err == nil {
+					t.Fatal("expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if !out.Equal(s.out) {
+				t.Fatalf("expected %s, got %s", s.out, out)
+			}
+		})
+	}
+}
+
+func TestManifestWithMetadataDefaults(t *testing.T) {
+	str := func(s string) tftypes.Value {
+		return tftypes.NewValue(tftypes.String, s)
+	}
+	object := func(atts map[string]tftypes.Value) tftypes.Value {
+		types := make(map[string]tftypes.Type)
+		for k, v := range atts {
+			types[k] = v.Type()
+		}
+		return tftypes.NewValue(tftypes.Object{AttributeTypes: types}, atts)
+	}
+	manifest := func(metadata map[string]tftypes.Value) tftypes.Value {
+		return object(map[string]tftypes.Value{
+			"apiVersion": str("v1"),
+			"kind":       str("ConfigMap"),
+			"metadata":   object(metadata),
+		})
+	}
+	stringMap := func(vals map[string]tftypes.Value) tftypes.Value {
+		return tftypes.NewValue(tftypes.Map{AttributeType: tftypes.String}, vals)
+	}
+	defaults := map[string]string{"cost-center": "42", "owner": "platform"}
+
+	samples := []struct {
+		in  tftypes.Value
+		out tftypes.Value
+	}{
+		{
+			in: manifest(map[string]tftypes.Value{"name": str("foo")}),
+			out: manifest(map[string]tftypes.Value{
+				"name":   str("foo"),
+				"labels": stringMap(map[string]tftypes.Value{"cost-center": str("42"), "owner": str("platform")}),
+			}),
+		},
+		{
+			// labels set by the manifest take precedence
+			in: manifest(map[string]tftypes.Value{
+				"name":   str("foo"),
+				"labels": object(map[string]tftypes.Value{"owner": str("web"), "app": str("web")}),
+			}),
+			out: manifest(map[string]tftypes.Value{
+				"name":   str("foo"),
+				"labels": object(map[string]tftypes.Value{"owner": str("web"), "app": str("web"), "cost-center": str("42")}),
+			}),
+		},
+		{
+			in: manifest(map[string]tftypes.Value{
+				"name":   str("foo"),
+				"labels": stringMap(map[string]tftypes.Value{"app": str("web")}),
+			}),
+			out: manifest(map[string]tftypes.Value{
+				"name":   str("foo"),
+				"labels": stringMap(map[string]tftypes.Value{"app": str("web"), "cost-center": str("42"), "owner": str("platform")}),
+			}),
+		},
+		{
+			in: manifest(map[string]tftypes.Value{
+				"name":   str("foo"),
+				"labels": tftypes.NewValue(tftypes.DynamicPseudoType, nil),
+			}),
+			out: manifest(map[string]tftypes.Value{
+				"name":   str("foo"),
+				"labels": stringMap(map[string]tftypes.Value{"cost-center": str("42"), "owner": str("platform")}),
+			}),
+		},
+		{
+			// the labels aren't known yet, they are merged once they are
+			in: manifest(map[string]tftypes.Value{
+				"name":   str("foo"),
+				"labels": tftypes.NewValue(tftypes.Map{AttributeType: tftypes.String}, tftypes.UnknownValue),
+			}),
+			out: manifest(map[string]tftypes.Value{
+				"name":   str("foo"),
+				"labels": tftypes.NewValue(tftypes.Map{AttributeType: tftypes.String}, tftypes.UnknownValue),
+			}),
+		},
+	}
+
+	for i, s := range samples {
+		t.Run(fmt.Sprintf("sample%d", i+1), func(t *testing.T) {
+			out, err := manifestWithMetadataDefaults(s.in, "labels", defaults)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if !out.Equal(s.out) {
+				t.Fatalf("expected %s, got %s", s.out, out)
+			}
+		})
+	}
+}
+
+func TestRemoveIgnoredMetadata(t *testing.T) {
+	ps := &RawProviderServer{
+		ignoreLabels:       []*regexp.Regexp{regexp.MustCompile(`^security\.istio\.io/`), regexp.MustCompile(`^service\.istio\.io/`)},
+		ignoreAnnotations:  []*regexp.Regexp{regexp.MustCompile(`cert-manager\.io`), regexp.MustCompile(`^kubectl\.kubernetes\.io/`)},
+		defaultAnnotations: map[string]string{"kubectl.kubernetes.io/default-container": "web"},
+	}
+	obj := map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "Pod",
+		"metadata": map[string]interface{}{
+			"name": "web",
+			"labels": map[string]interface{}{
+				"app":                              "web",
+				"security.istio.io/tlsMode":        "istio",
+				"service.istio.io/canonical-name":  "web",
+				"service.istio.io/canonical-owner": "platform",
+			},
+			"annotations": map[string]interface{}{
+				"cert-manager.io/issuer":                  "letsencrypt",
+				"kubectl.kubernetes.io/default-container": "web",
+			},
+		},
+	}
+	manifest := tftypes.NewValue(tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+		"metadata": tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+			"name":   tftypes.String,
+			"labels": tftypes.Map{AttributeType: tftypes.String},
+		}},
+	}}, map[string]tftypes.Value{
+		"metadata": tftypes.NewValue(tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+			"name":   tftypes.String,
+			"labels": tftypes.Map{AttributeType: tftypes.String},
+		}}, map[string]tftypes.Value{
+			"name": tftypes.NewValue(tftypes.String, "web"),
+			"labels": tftypes.NewValue(tftypes.Map{AttributeType: tftypes.String}, map[string]tftypes.Value{
+				"app":                              tftypes.NewValue(tftypes.String, "web"),
+				"service.istio.io/canonical-owner": tftypes.NewValue(tftypes.String, "platform"),
+			}),
+		}),
+	})
+
+	ps.removeIgnoredMetadata(obj, manifest)
+
+	expected := map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "Pod",
+		"metadata": map[string]interface{}{
+			"name": "web",
+			"labels": map[string]interface{}{
+				"app":                              "web",
+				"service.istio.io/canonical-owner": "platform",
+			},
+			"annotations": map[string]interface{}{
+				"kubectl.kubernetes.io/default-container": "web",
+			},
+		},
+	}
+	if !reflect.DeepEqual(expected, obj) {
+		t.Fatalf("expected %#v, got %#v", expected, obj)
+	}
+
+	ps.defaultAnnotations = nil
+	ps.removeIgnoredMetadata(obj, tftypes.NewValue(tftypes.DynamicPseudoType, nil))
+	if _, ok := obj["metadata"].(map[string]interface{})["annotations"]; ok {
+		t.Fatalf("expected empty annotations to be removed, got %#v", obj)
+	}
+}
@@ -2,10 +2,13 @@ package provider
 
 import (
 	"context"
+	"regexp"
+	"time"
 
 	"github.com/davecgh/go-spew/spew"
 	"github.com/hashicorp/go-hclog"
 	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
 	"github.com/hashicorp/terraform-provider-kubernetes-alpha/openapi"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -26,26 +29,60 @@ type RawProviderServer struct {
 	// Since the provider is essentially a gRPC server, the execution flow is dictated by the order of the client (Terraform) request calls.
 	// Thus it needs a way to persist state between the gRPC calls. These attributes store values that need to be persisted between gRPC calls,
 	// such as instances of the Kubernetes clients, configuration options needed at runtime.
-	logger          hclog.Logger
-	clientConfig    *rest.Config
-	dynamicClient   dynamic.Interface
+	logger       hclog.Logger
+	clientConfig *rest.Config
+	// clientConfigUnknown is set when the provider configuration depends on values that are not known
+	// until apply, in which case planning can't reach the API server and is deferred to apply time
+	clientConfigUnknown bool
+	// defaultNamespace is set on manifests of namespaced resources which don't specify a namespace
+	defaultNamespace string
+	// defaultLabels and defaultAnnotations are added to the metadata of every manifest
+	defaultLabels      map[string]string
+	defaultAnnotations map[string]string
+	// ignoreLabels and ignoreAnnotations match the metadata that is left out of 'object' unless it is set in the manifest
+	ignoreLabels      []*regexp.Regexp
+	ignoreAnnotations []*regexp.Regexp
+	// fieldManagerDefaults are the server-side apply settings for resources which don't configure their own
+	fieldManagerDefaults fieldManagerSettings
+	// timeoutDefaults bound the operations of resources which don't configure their own timeouts
+	timeoutDefaults timeoutSettings
+	// waitPollInterval is the time between two reads of a resource in 'wait_for' when watching it is not supported
+	waitPollInterval time.Duration
+	dynamicClient    dynamic.Interface
+	// watchClient is a dynamic client without request timeout, for the watches of 'wait_for'
+	watchClient     dynamic.Interface
 	discoveryClient discovery.DiscoveryInterface
 	restMapper      meta.RESTMapper
 	restClient      rest.Interface
 	OAPIFoundry     openapi.Foundry
 }
 
-// PrepareProviderConfig function
+// PrepareProviderConfig validates the provider configuration without contacting the API server
 func (s *RawProviderServer) PrepareProviderConfig(ctx context.Context, req *tfprotov5.PrepareProviderConfigRequest) (*tfprotov5.PrepareProviderConfigResponse, error) {
 	s.logger.Trace("[PrepareProviderConfig][Request]\n%s\n", spew.Sdump(*req))
-	resp := &tfprotov5.PrepareProviderConfigResponse{}
-	return resp, nil
-}
+	resp := &tfprotov5.PrepareProviderConfigResponse{PreparedConfig: req.Config}
 
-// ValidateDataSourceConfig function
-func (s *RawProviderServer) ValidateDataSourceConfig(ctx context.Context, req *tfprotov5.ValidateDataSourceConfigRequest) (*tfprotov5.ValidateDataSourceConfigResponse, error) {
-	s.logger.Trace("[ValidateDataSourceConfig][Request]\n%s\n", spew.Sdump(*req))
-	resp := &tfprotov5.ValidateDataSourceConfigResponse{}
+	cfgType := GetTypeFromSchema(GetProviderConfigSchema())
+	cfgVal, err := req.Config.Unmarshal(cfgType)
+	if err != nil {
+		resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
+			Severity: tfprotov5.DiagnosticSeverityError,
+			Summary:  "Failed to decode PrepareProviderConfig request parameter",
+			Detail:   err.Error(),
+		})
+		return resp, nil
+	}
+	var providerConfig map[string]tftypes.Value
+	err = cfgVal.As(&providerConfig)
+	if err != nil {
+		resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
+			Severity: tfprotov5.DiagnosticSeverityError,
+			Summary:  "Failed to extract provider configuration values",
+			Detail:   err.Error(),
+		})
+		return resp, nil
+	}
+	resp.Diagnostics = append(resp.Diagnostics, validateProviderConfig(providerConfig)...)
 	return resp, nil
 }
 
@@ -57,7 +94,20 @@ func (s *RawProviderServer) UpgradeResourceState(ctx context.Context, req *tfpro
 	sch := GetProviderResourceSchema()
 	rt := GetObjectTypeFromSchema(sch[req.TypeName])
 
-	rv, err := req.RawState.Unmarshal(rt)
+	st := rt
+	if req.TypeName == "kubernetes_manifest" && req.Version < 2 {
+		// before version 2, 'wait_for' was an object with only the 'fields' attribute
+		at := make(map[string]tftypes.Type)
+		for k, t := range rt.(tftypes.Object).AttributeTypes {
+			at[k] = t
+		}
+		at["wait_for"] = tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+			"fields": tftypes.Map{AttributeType: tftypes.String},
+		}}
+		st = tftypes.Object{AttributeTypes: at}
+	}
+
+	rv, err := req.RawState.Unmarshal(st)
 	if err != nil {
 		resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
 			Severity: tfprotov5.DiagnosticSeverityError,
@@ -79,22 +129,6 @@ func (s *RawProviderServer) UpgradeResourceState(ctx context.Context, req *tfpro
 	return resp, nil
 }
 
-// ImportResourceState function
-func (*RawProviderServer) ImportResourceState(ctx context.Context, req *tfprotov5.ImportResourceStateRequest) (*tfprotov5.ImportResourceStateResponse, error) {
-	// Terraform only gives us the schema name of the resource and an ID string, as passed by the user on the command line.
-	// The ID should be a combination of a Kubernetes GRV and a namespace/name type of resource identifier.
-	// Without the user supplying the GRV there is no way to fully identify the resource when making the Get API call to K8s.
-	// Presumably the Kubernetes API machinery already has a standard for expressing such a group. We should look there first.
-	return nil, status.Errorf(codes.Unimplemented, "method ImportResourceState not implemented")
-}
-
-// ReadDataSource function
-func (s *RawProviderServer) ReadDataSource(ctx context.Context, req *tfprotov5.ReadDataSourceRequest) (*tfprotov5.ReadDataSourceResponse, error) {
-	s.logger.Trace("[ReadDataSource][Request]\n%s\n", spew.Sdump(*req))
-
-	return nil, status.Errorf(codes.Unimplemented, "method ReadDataSource not implemented")
-}
-
 // StopProvider function
 func (s *RawProviderServer) StopProvider(ctx context.Context, req *tfprotov5.StopProviderRequest) (*tfprotov5.StopProviderResponse, error) {
 	s.logger.Trace("[StopProvider][Request]\n%s\n", spew.Sdump(*req))
@@ -0,0 +1,64 @@
+package provider
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// defaultTimeout bounds create, update and delete operations when no timeout is configured
+const defaultTimeout time.Duration = 20 * time.Minute
+
+// timeoutOperations are the attributes of a 'timeouts' block
+var timeoutOperations = []string{"create", "update", "delete"}
+
+// timeoutSettings are the durations set in a 'timeouts' block, keyed by operation. Unset operations are missing.
+type timeoutSettings map[string]time.Duration
+
+// parseTimeoutsBlock extracts the durations from the value of a 'timeouts' block.
+// Blocks that aren't set or not known yet produce empty settings.
+func parseTimeoutsBlock(v tftypes.Value) (timeoutSettings, error) {
+	ts := make(timeoutSettings)
+	if v.IsNull() || !v.IsKnown() {
+		return ts, nil
+	}
+	var atts map[string]tftypes.Value
+	err := v.As(&atts)
+	if err != nil {
+		return ts, err
+	}
+	for _, op := range timeoutOperations {
+		a, ok := atts[op]
+		if !ok || a.IsNull() || !a.IsKnown() {
+			continue
+		}
+		var s string
+		err = a.As(&s)
+		if err != nil {
+			return ts, err
+		}
+		d, err := time.ParseDuration(s)
+		if err != nil || d <= 0 {
+			return ts, fmt.Errorf("'%s' must be a positive duration like \"10m\", got %q", op, s)
+		}
+		ts[op] = d
+	}
+	return ts, nil
+}
+
+// timeout returns how long an operation on a resource may take, falling back
+// to the timeouts of the provider and then to defaultTimeout
+func (s *RawProviderServer) timeout(resourceBlock tftypes.Value, op string) (time.Duration, error) {
+	ts, err := parseTimeoutsBlock(resourceBlock)
+	if err != nil {
+		return 0, err
+	}
+	if d, ok := ts[op]; ok {
+		return d, nil
+	}
+	if d, ok := s.timeoutDefaults[op]; ok {
+		return d, nil
+	}
+	return defaultTimeout, nil
+}
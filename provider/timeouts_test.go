@@ -0,0 +1,208 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/dynamic"
+)
+
+func TestTimeout(t *testing.T) {
+	blockType := GetObjectTypeFromSchema(GetProviderResourceSchema()["kubernetes_manifest"]).(tftypes.Object).AttributeTypes["timeouts"]
+	block := func(atts map[string]string) tftypes.Value {
+		vals := make(map[string]tftypes.Value)
+		for _, op := range timeoutOperations {
+			vals[op] = tftypes.NewValue(tftypes.String, nil)
+		}
+		for k, v := range atts {
+			vals[k] = tftypes.NewValue(tftypes.String, v)
+		}
+		return tftypes.NewValue(blockType, vals)
+	}
+
+	samples := []struct {
+		provider timeoutSettings
+		resource tftypes.Value
+		op       string
+		timeout  time.Duration
+		err      bool
+	}{
+		{
+			resource: tftypes.NewValue(blockType, nil),
+			op:       "create",
+			timeout:  defaultTimeout,
+		},
+		{
+			provider: timeoutSettings{"delete": 2 * time.Minute},
+			resource: tftypes.NewValue(blockType, nil),
+			op:       "delete",
+			timeout:  2 * time.Minute,
+		},
+		{
+			provider: timeoutSettings{"update": 2 * time.Minute},
+			resource: block(map[string]string{"update": "90s"}),
+			op:       "update",
+			timeout:  90 * time.Second,
+		},
+		{
+			provider: timeoutSettings{"update": 2 * time.Minute},
+			resource: block(map[string]string{"create": "1h"}),
+			op:       "update",
+			timeout:  2 * time.Minute,
+		},
+		{
+			resource: block(map[string]string{"create": "ten minutes"}),
+			op:       "create",
+			err:      true,
+		},
+		{
+			resource: block(map[string]string{"delete": "-5m"}),
+			op:       "delete",
+			err:      true,
+		},
+	}
+
+	for i, s := range samples {
+		t.Run(fmt.Sprintf("sample%d", i+1), func(t *testing.T) {
+			ps := &RawProviderServer{logger: hclog.NewNullLogger(), timeoutDefaults: s.provider}
+			timeout, err := ps.timeout(s.resource, s.op)
+			if s.err {
+				if err == nil {
+					t.Fatal("expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if timeout != s.timeout {
+				t.Fatalf("expected timeout %s, got %s", s.timeout, timeout)
+			}
+		})
+	}
+}
+
+// staticResource serves the same object on every GET and doesn't support watch
+type staticResource struct {
+	dynamic.ResourceInterface
+	obj map[string]interface{}
+}
+
+func (r *staticResource) Get(ctx context.Context, name string, options metav1.GetOptions, subresources ...string) (*unstructured.Unstructured, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return &unstructured.Unstructured{Object: r.obj}, nil
+}
+
+func (r *staticResource) Watch(ctx context.Context, opts metav1.ListOptions) (watch.Interface, error) {
+	return nil, apierrors.NewMethodNotSupported(schema.GroupResource{Resource: "pods"}, "watch")
+}
+
+func TestFieldWaiterTimeout(t *testing.T) {
+	rt := tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+		"metadata": tftypes.Object{AttributeTypes: map[string]tftypes.Type{"name": tftypes.String}},
+		"status":   tftypes.Object{AttributeTypes: map[string]tftypes.Type{"phase": tftypes.String}},
+	}}
+	rs := &staticResource{obj: map[string]interface{}{
+		"metadata": map[string]interface{}{"name": "web"},
+		"status":   map[string]interface{}{"phase": "Pending"},
+	}}
+	w := &FieldWaiter{
+		resource:     rs,
+		resourceName: "web",
+		resourceType: rt,
+		fieldMatchers: []FieldMatcher{
+			{"status.phase", tftypes.NewAttributePath().WithAttributeName("status").WithAttributeName("phase"), regexp.MustCompile("^Running$")},
+			{"status.podIP", tftypes.NewAttributePath().WithAttributeName("status").WithAttributeName("podIP"), regexp.MustCompile(".+")},
+		},
+		pollInterval: 10 * time.Millisecond,
+		logger:       hclog.NewNullLogger(),
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	err := w.Wait(ctx)
+	var werr *WaitTimeoutError
+	if !errors.As(err, &werr) {
+		t.Fatalf("expected a timeout error, got %v", err)
+	}
+	if werr.LastObserved["status.phase"] != `"Pending"` {
+		t.Fatalf("expected last observed phase to be reported, got %q", werr.LastObserved["status.phase"])
+	}
+	if werr.LastObserved["status.podIP"] != "(not present)" {
+		t.Fatalf("expected missing field to be reported, got %q", werr.LastObserved["status.podIP"])
+	}
+}
+
+// deletingResource serves an object for a number of GETs, and answers NotFound once they are used up
+type deletingResource struct {
+	dynamic.ResourceInterface
+	obj  map[string]interface{}
+	gets int
+}
+
+func (r *deletingResource) Get(ctx context.Context, name string, options metav1.GetOptions, subresources ...string) (*unstructured.Unstructured, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if r.gets == 0 {
+		return nil, apierrors.NewNotFound(schema.GroupResource{Resource: "namespaces"}, name)
+	}
+	r.gets--
+	return &unstructured.Unstructured{Object: r.obj}, nil
+}
+
+func TestWaitForDeletion(t *testing.T) {
+	obj := map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"name":       "prod",
+			"finalizers": []interface{}{"kubernetes", "example.com/cleanup"},
+		},
+	}
+	samples := []struct {
+		gets    int
+		timeout bool
+	}{
+		{gets: 0},
+		{gets: 3},
+		{gets: 1000, timeout: true},
+	}
+
+	for i, s := range samples {
+		t.Run(fmt.Sprintf("sample%d", i+1), func(t *testing.T) {
+			rs := &deletingResource{obj: obj, gets: s.gets}
+			ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+			defer cancel()
+			err := waitForDeletion(ctx, rs, "prod", 10*time.Millisecond)
+			if !s.timeout {
+				if err != nil {
+					t.Fatalf("unexpected error: %s", err)
+				}
+				if rs.gets != 0 {
+					t.Fatalf("expected to wait until the resource is gone, %d reads left", rs.gets)
+				}
+				return
+			}
+			if !errors.Is(err, context.DeadlineExceeded) {
+				t.Fatalf("expected a timeout error, got %v", err)
+			}
+			d := timeoutDiagnostic(`Timed out deleting resource "prod"`, "delete", 100*time.Millisecond, err)
+			if !strings.Contains(d.Detail, "kubernetes, example.com/cleanup") {
+				t.Fatalf("expected the remaining finalizers to be reported, got %q", d.Detail)
+			}
+		})
+	}
+}
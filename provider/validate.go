@@ -2,10 +2,20 @@ package provider
 
 import (
 	"context"
+	"encoding/pem"
 	"fmt"
+	"net/url"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
 
 	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
 	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/mitchellh/go-homedir"
+	apimachineryschema "k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/client-go/rest"
 )
 
 // ValidateResourceTypeConfig function
@@ -49,6 +59,66 @@ func (s *RawProviderServer) ValidateResourceTypeConfig(ctx context.Context, req
 		return resp, nil
 	}
 
+	if _, err := parseFieldManagerBlock(configVal["field_manager"]); err != nil {
+		resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
+			Severity:  tfprotov5.DiagnosticSeverityError,
+			Summary:   "Invalid field_manager block",
+			Detail:    err.Error(),
+			Attribute: tftypes.NewAttributePath().WithAttributeName("field_manager"),
+		})
+	}
+
+	if _, err := parseTimeoutsBlock(configVal["timeouts"]); err != nil {
+		resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
+			Severity:  tfprotov5.DiagnosticSeverityError,
+			Summary:   "Invalid timeouts block",
+			Detail:    err.Error(),
+			Attribute: tftypes.NewAttributePath().WithAttributeName("timeouts"),
+		})
+	}
+
+	waitFor, err := parseWaitForBlock(configVal["wait_for"])
+	if err != nil {
+		waitForAtt := tftypes.NewAttributePath().WithAttributeName("wait_for")
+		if ae, ok := err.(*waitForAttributeError); ok {
+			waitForAtt = waitForAtt.WithAttributeName(ae.attribute)
+		}
+		resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
+			Severity:  tfprotov5.DiagnosticSeverityError,
+			Summary:   "Invalid wait_for attribute",
+			Detail:    err.Error(),
+			Attribute: waitForAtt,
+		})
+	}
+
+	if cf := configVal["computed_fields"]; !cf.IsNull() && cf.IsFullyKnown() {
+		var fields []tftypes.Value
+		err = cf.As(&fields)
+		if err != nil {
+			resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
+				Severity:  tfprotov5.DiagnosticSeverityError,
+				Summary:   "Failed to extract computed_fields from resource configuration",
+				Detail:    err.Error(),
+				Attribute: tftypes.NewAttributePath().WithAttributeName("computed_fields"),
+			})
+		}
+		for i, f := range fields {
+			var fp string
+			err = f.As(&fp)
+			if err == nil {
+				_, err = FieldPathToTftypesPath(fp)
+			}
+			if err != nil {
+				resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
+					Severity:  tfprotov5.DiagnosticSeverityError,
+					Summary:   "Invalid attribute path in computed_fields",
+					Detail:    err.Error(),
+					Attribute: tftypes.NewAttributePath().WithAttributeName("computed_fields").WithElementKeyInt(int64(i)),
+				})
+			}
+		}
+	}
+
 	manifest, ok := configVal["manifest"]
 	if !ok {
 		resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
@@ -101,6 +171,22 @@ func (s *RawProviderServer) ValidateResourceTypeConfig(ctx context.Context, req
 		}
 	}
 
+	if waitFor.ready {
+		var apiVersion, kind string
+		errV := rawManifest["apiVersion"].As(&apiVersion)
+		errK := rawManifest["kind"].As(&kind)
+		if errV == nil && errK == nil && apiVersion != "" && kind != "" {
+			if _, err := healthCheckFor(apiVersion, kind); err != nil {
+				resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
+					Severity:  tfprotov5.DiagnosticSeverityError,
+					Summary:   "Invalid wait_for attribute",
+					Detail:    err.Error(),
+					Attribute: tftypes.NewAttributePath().WithAttributeName("wait_for").WithAttributeName("ready"),
+				})
+			}
+		}
+	}
+
 	return resp, nil
 }
 
@@ -180,3 +266,209 @@ func (s *RawProviderServer) validateResourceOnline(manifest *tftypes.Value) (dia
 	}
 	return
 }
+
+// validateProviderConfig checks the provider configuration for errors that can be found without
+// contacting the API server. Only values from the configuration itself are checked - attributes
+// overridden by KUBE_* environment variables are validated by ConfigureProvider once they are applied.
+func validateProviderConfig(cfg map[string]tftypes.Value) (diags []*tfprotov5.Diagnostic) {
+	invalid := func(name string, detail string) {
+		diags = append(diags, &tfprotov5.Diagnostic{
+			Severity:  tfprotov5.DiagnosticSeverityInvalid,
+			Summary:   "Invalid attribute in provider configuration",
+			Detail:    detail,
+			Attribute: tftypes.NewAttributePath().WithAttributeName(name),
+		})
+	}
+	isSet := func(name string) bool {
+		v, ok := cfg[name]
+		return ok && !v.IsNull()
+	}
+	// values that are not known yet are checked by ConfigureProvider during apply
+	knownString := func(name string) (string, bool) {
+		var s string
+		v, ok := cfg[name]
+		if !ok || v.IsNull() || !v.IsKnown() || v.As(&s) != nil {
+			return "", false
+		}
+		return s, s != ""
+	}
+	// ConfigureProvider replaces these attributes with the environment variable when it is set
+	overridden := func(env string) bool {
+		v, ok := os.LookupEnv(env)
+		return ok && v != ""
+	}
+
+	if p, ok := knownString("config_path"); ok && !overridden("KUBE_CONFIG_PATH") {
+		if err := checkConfigPath(p); err != nil {
+			invalid("config_path", "'config_path' refers to an invalid path: "+err.Error())
+		}
+	}
+	if v, ok := cfg["config_paths"]; ok && !v.IsNull() && v.IsKnown() && !overridden("KUBE_CONFIG_PATHS") {
+		var paths []tftypes.Value
+		if v.As(&paths) == nil {
+			for i, pv := range paths {
+				var p string
+				if pv.IsNull() || !pv.IsKnown() || pv.As(&p) != nil || p == "" {
+					continue
+				}
+				if err := checkConfigPath(p); err != nil {
+					diags = append(diags, &tfprotov5.Diagnostic{
+						Severity:  tfprotov5.DiagnosticSeverityInvalid,
+						Summary:   "Invalid attribute in provider configuration",
+						Detail:    "'config_paths' refers to an invalid path: " + err.Error(),
+						Attribute: tftypes.NewAttributePath().WithAttributeName("config_paths").WithElementKeyInt(int64(i)),
+					})
+				}
+			}
+		}
+	}
+
+	if host, ok := knownString("host"); ok && !overridden("KUBE_HOST") {
+		if err := checkHost(host); err != nil {
+			invalid("host", err.Error())
+		}
+	}
+
+	if cc, ok := knownString("client_certificate"); ok && !overridden("KUBE_CLIENT_CERT_DATA") {
+		if err := checkPEMCertificate("client_certificate", cc); err != nil {
+			invalid("client_certificate", err.Error())
+		}
+	}
+	if ca, ok := knownString("cluster_ca_certificate"); ok && !overridden("KUBE_CLUSTER_CA_CERT_DATA") {
+		if err := checkPEMCertificate("cluster_ca_certificate", ca); err != nil {
+			invalid("cluster_ca_certificate", err.Error())
+		}
+	}
+	if ck, ok := knownString("client_key"); ok && !overridden("KUBE_CLIENT_KEY_DATA") {
+		if err := checkPEMPrivateKey("client_key", ck); err != nil {
+			invalid("client_key", err.Error())
+		}
+	}
+
+	if ns, ok := knownString("default_namespace"); ok && !overridden("KUBE_DEFAULT_NAMESPACE") {
+		if err := checkNamespaceName(ns); err != nil {
+			invalid("default_namespace", err.Error())
+		}
+	}
+
+	for _, name := range []string{"default_labels", "default_annotations"} {
+		m, err := providerConfigStringMap(cfg[name])
+		if err != nil {
+			continue
+		}
+		for _, e := range checkMetadataDefaults(name, m) {
+			invalid(name, fmt.Sprintf("'%s' %s", name, e))
+		}
+	}
+
+	for _, name := range []string{"ignore_labels", "ignore_annotations"} {
+		exprs, err := providerConfigStringList(cfg[name])
+		if err != nil {
+			continue
+		}
+		for _, e := range exprs {
+			if _, err := regexp.Compile(e); err != nil {
+				invalid(name, fmt.Sprintf("'%s' contains an invalid regular expression: %s", name, err))
+			}
+		}
+	}
+
+	if _, err := parseFieldManagerBlock(cfg["field_manager"]); err != nil {
+		invalid("field_manager", "'field_manager' "+err.Error())
+	}
+	if _, err := parseTimeoutsBlock(cfg["timeouts"]); err != nil {
+		invalid("timeouts", "'timeouts' "+err.Error())
+	}
+	if _, _, err := parseImpersonateBlock(cfg["impersonate"]); err != nil {
+		invalid("impersonate", "'impersonate' "+err.Error())
+	}
+	if _, _, err := parseExecConfig(cfg["exec"]); err != nil {
+		diags = append(diags, &tfprotov5.Diagnostic{
+			Severity:  tfprotov5.DiagnosticSeverityInvalid,
+			Summary:   "Invalid attribute in provider configuration",
+			Detail:    err.Error(),
+			Attribute: err.(*execConfigError).path(),
+		})
+	}
+
+	if isSet("token") {
+		for _, a := range []string{"client_certificate", "client_certificate_file", "client_key", "client_key_file"} {
+			if isSet(a) {
+				invalid("token", fmt.Sprintf("'token' and '%s' cannot be set at the same time, use either a token or a client certificate to authenticate", a))
+			}
+		}
+	}
+	if isSet("username") && !isSet("password") {
+		invalid("username", "'username' requires 'password' to be set")
+	}
+	if isSet("password") && !isSet("username") {
+		invalid("password", "'password' requires 'username' to be set")
+	}
+	return
+}
+
+// checkConfigPath returns an error if a kubeconfig path does not refer to an existing file
+func checkConfigPath(p string) error {
+	abs, err := homedir.Expand(p)
+	if err != nil {
+		return err
+	}
+	_, err = os.Stat(abs)
+	return err
+}
+
+// checkHost returns an error if 'host' can't be used as the address of the API server
+func checkHost(host string) error {
+	if _, err := url.ParseRequestURI(host); err != nil {
+		return fmt.Errorf("'host' is not a valid URL")
+	}
+	if _, _, err := rest.DefaultServerURL(host, "", apimachineryschema.GroupVersion{}, false); err != nil {
+		return fmt.Errorf("Invalid value for 'host': %s", err)
+	}
+	return nil
+}
+
+// checkPEMCertificate returns an error if the value of an attribute is not a PEM encoded certificate
+func checkPEMCertificate(name string, v string) error {
+	if b, _ := pem.Decode([]byte(v)); b == nil || b.Type != "CERTIFICATE" {
+		return fmt.Errorf("'%s' is not a valid PEM encoded certificate", name)
+	}
+	return nil
+}
+
+// checkPEMPrivateKey returns an error if the value of an attribute is not a PEM encoded private key
+func checkPEMPrivateKey(name string, v string) error {
+	if b, _ := pem.Decode([]byte(v)); b == nil || !strings.Contains(b.Type, "PRIVATE KEY") {
+		return fmt.Errorf("'%s' is not a valid PEM encoded private key", name)
+	}
+	return nil
+}
+
+// checkNamespaceName returns an error if 'default_namespace' is not a valid namespace name
+func checkNamespaceName(ns string) error {
+	if errs := validation.IsDNS1123Label(ns); len(errs) > 0 {
+		return fmt.Errorf("'default_namespace' is not a valid namespace name: %s", strings.Join(errs, ", "))
+	}
+	return nil
+}
+
+// checkMetadataDefaults returns the problems with the default labels or annotations from the provider configuration
+func checkMetadataDefaults(name string, defaults map[string]string) (errs []string) {
+	keys := make([]string, 0, len(defaults))
+	for k := range defaults {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		for _, e := range validation.IsQualifiedName(strings.ToLower(k)) {
+			errs = append(errs, fmt.Sprintf("key %q is invalid: %s", k, e))
+		}
+		if name != "default_labels" {
+			continue
+		}
+		for _, e := range validation.IsValidLabelValue(defaults[k]) {
+			errs = append(errs, fmt.Sprintf("value of %q is invalid: %s", k, e))
+		}
+	}
+	return
+}
@@ -3,8 +3,12 @@ package provider
 import (
 	"context"
 	"fmt"
+	"math"
 	"math/big"
 	"regexp"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/hashicorp/go-hclog"
 	"github.com/hashicorp/terraform-plugin-go/tftypes"
@@ -15,15 +19,19 @@ import (
 	"github.com/hashicorp/hcl/v2/hclsyntax"
 	"k8s.io/apimachinery/pkg/api/errors"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/fields"
+	utilnet "k8s.io/apimachinery/pkg/util/net"
+	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/client-go/dynamic"
 )
 
-func (s *RawProviderServer) waitForCompletion(ctx context.Context, waitForBlock tftypes.Value, rs dynamic.ResourceInterface, rname string, rtype tftypes.Type) error {
+func (s *RawProviderServer) waitForCompletion(ctx context.Context, waitForBlock tftypes.Value, rs dynamic.ResourceInterface, ws dynamic.ResourceInterface, rname string, rtype tftypes.Type) error {
 	if waitForBlock.IsNull() || !waitForBlock.IsKnown() {
 		return nil
 	}
 
-	waiter, err := NewResourceWaiter(rs, rname, rtype, waitForBlock, s.logger)
+	waiter, err := NewResourceWaiter(rs, ws, rname, rtype, waitForBlock, s.waitPollInterval, s.logger)
 	if err != nil {
 		return err
 	}
@@ -35,20 +43,140 @@ type Waiter interface {
 	Wait(context.Context) error
 }
 
-// NewResourceWaiter constructs an appropriate Waiter using the supplied waitForBlock configuration
-func NewResourceWaiter(resource dynamic.ResourceInterface, resourceName string, resourceType tftypes.Type, waitForBlock tftypes.Value, hl hclog.Logger) (Waiter, error) {
+const (
+	// defaultWaitPollInterval is the time between two reads of a resource that is waited on,
+	// when the API server doesn't support watch
+	defaultWaitPollInterval = 2 * time.Second
+	// waitWatchBackoffMin and waitWatchBackoffMax bound the delay before a failed watch is retried
+	waitWatchBackoffMin = 1 * time.Second
+	waitWatchBackoffMax = 30 * time.Second
+)
+
+// WaitTimeoutError is returned by a Waiter when its context expires before the conditions are met
+type WaitTimeoutError struct {
+	// LastObserved holds the last value read for each field that was waited on
+	LastObserved map[string]string
+}
+
+func (e *WaitTimeoutError) Error() string {
+	fields := make([]string, 0, len(e.LastObserved))
+	for f := range e.LastObserved {
+		fields = append(fields, f)
+	}
+	sort.Strings(fields)
+	var b strings.Builder
+	b.WriteString("timed out waiting for the conditions in wait_for")
+	if len(fields) > 0 {
+		b.WriteString(", last observed values:")
+	}
+	for _, f := range fields {
+		fmt.Fprintf(&b, "\n  %s: %s", f, e.LastObserved[f])
+	}
+	return b.String()
+}
+
+// NewResourceWaiter constructs an appropriate Waiter using the supplied waitForBlock configuration.
+// The resource is watched through watchClient, which shouldn't have a request timeout.
+func NewResourceWaiter(resource dynamic.ResourceInterface, watchClient dynamic.ResourceInterface, resourceName string, resourceType tftypes.Type, waitForBlock tftypes.Value, pollInterval time.Duration, hl hclog.Logger) (Waiter, error) {
+	wf, err := parseWaitForBlock(waitForBlock)
+	if err != nil {
+		return nil, err
+	}
+	if len(wf.fields) == 0 && len(wf.conditions) == 0 && wf.expression == nil && !wf.ready {
+		return &NoopWaiter{}, nil
+	}
+	if pollInterval <= 0 {
+		pollInterval = defaultWaitPollInterval
+	}
+
+	return &FieldWaiter{
+		resource,
+		watchClient,
+		resourceName,
+		resourceType,
+		wf.fields,
+		wf.conditions,
+		wf.expression,
+		wf.ready,
+		pollInterval,
+		hl,
+	}, nil
+
+}
+
+// waitForSettings are the checks configured in the 'wait_for' attribute
+type waitForSettings struct {
+	fields     []FieldMatcher
+	conditions []ConditionMatcher
+	expression *ExpressionMatcher
+	ready      bool
+}
+
+// waitForAttributeError is returned by parseWaitForBlock when an attribute of 'wait_for' is invalid
+type waitForAttributeError struct {
+	attribute string
+	err       error
+}
+
+func (e *waitForAttributeError) Error() string {
+	return e.err.Error()
+}
+
+// parseWaitForBlock extracts the checks from the value of the 'wait_for' attribute.
+// Values that are not known yet produce no checks.
+func parseWaitForBlock(waitForBlock tftypes.Value) (waitForSettings, error) {
+	var wf waitForSettings
+	if waitForBlock.IsNull() || !waitForBlock.IsFullyKnown() {
+		return wf, nil
+	}
+	if !waitForBlock.Type().Is(tftypes.Object{}) && !waitForBlock.Type().Is(tftypes.Map{}) {
+		return wf, fmt.Errorf(`"wait_for" should be an object with "fields", "condition", "expression" or "ready" attributes`)
+	}
 	var waitForBlockVal map[string]tftypes.Value
 	err := waitForBlock.As(&waitForBlockVal)
 	if err != nil {
-		return nil, err
+		return wf, err
+	}
+	for k := range waitForBlockVal {
+		if k != "fields" && k != "condition" && k != "expression" && k != "ready" {
+			return wf, fmt.Errorf("unsupported attribute %q in \"wait_for\"", k)
+		}
 	}
 
-	fields, ok := waitForBlockVal["fields"]
-	if !ok || fields.IsNull() || !fields.IsKnown() {
-		return &NoopWaiter{}, nil
+	wf.fields, err = parseFieldMatchers(waitForBlockVal["fields"])
+	if err != nil {
+		return wf, &waitForAttributeError{"fields", err}
+	}
+	wf.conditions, err = parseConditionMatchers(waitForBlockVal["condition"])
+	if err != nil {
+		return wf, &waitForAttributeError{"condition", err}
+	}
+	if e := waitForBlockVal["expression"]; !e.IsNull() {
+		if !e.Type().Is(tftypes.String) {
+			return wf, &waitForAttributeError{"expression", fmt.Errorf(`"expression" should be a string`)}
+		}
+		var expr string
+		e.As(&expr)
+		wf.expression, err = parseExpressionMatcher(expr)
+		if err != nil {
+			return wf, &waitForAttributeError{"expression", err}
+		}
 	}
+	if r := waitForBlockVal["ready"]; !r.IsNull() {
+		if !r.Type().Is(tftypes.Bool) {
+			return wf, &waitForAttributeError{"ready", fmt.Errorf(`"ready" should be a boolean`)}
+		}
+		r.As(&wf.ready)
+	}
+	return wf, nil
+}
 
-	if !fields.Type().Is(tftypes.Map{}) {
+// parseFieldMatchers builds a FieldMatcher for each entry of the 'fields' attribute of 'wait_for'
+func parseFieldMatchers(fields tftypes.Value) ([]FieldMatcher, error) {
+	if fields.IsNull() {
+		return nil, nil
+	}
+	if !fields.Type().Is(tftypes.Map{}) && !fields.Type().Is(tftypes.Object{}) {
 		return nil, fmt.Errorf(`"fields" should be a map of strings`)
 	}
 
@@ -57,6 +185,9 @@ func NewResourceWaiter(resource dynamic.ResourceInterface, resourceName string,
 	var matchers []FieldMatcher
 
 	for k, v := range vm {
+		if !v.Type().Is(tftypes.String) {
+			return nil, fmt.Errorf(`"fields" should be a map of strings`)
+		}
 		var expr string
 		v.As(&expr)
 		var re *regexp.Regexp
@@ -76,105 +207,409 @@ func NewResourceWaiter(resource dynamic.ResourceInterface, resourceName string,
 		if err != nil {
 			return nil, err
 		}
-		matchers = append(matchers, FieldMatcher{p, re})
+		matchers = append(matchers, FieldMatcher{k, p, re})
 	}
+	return matchers, nil
+}
 
-	return &FieldWaiter{
-		resource,
-		resourceName,
-		resourceType,
-		matchers,
-		hl,
-	}, nil
+// parseConditionMatchers builds a ConditionMatcher from the 'condition' attribute of 'wait_for',
+// which is either a single object or a list of them
+func parseConditionMatchers(condition tftypes.Value) ([]ConditionMatcher, error) {
+	if condition.IsNull() {
+		return nil, nil
+	}
+	var conds []tftypes.Value
+	switch {
+	case condition.Type().Is(tftypes.Object{}) || condition.Type().Is(tftypes.Map{}):
+		conds = []tftypes.Value{condition}
+	case condition.Type().Is(tftypes.List{}) || condition.Type().Is(tftypes.Tuple{}) || condition.Type().Is(tftypes.Set{}):
+		err := condition.As(&conds)
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf(`"condition" should be an object with "type", "status" and optional "reason" attributes`)
+	}
 
+	var matchers []ConditionMatcher
+	for _, c := range conds {
+		if !c.Type().Is(tftypes.Object{}) && !c.Type().Is(tftypes.Map{}) {
+			return nil, fmt.Errorf(`"condition" should be an object with "type", "status" and optional "reason" attributes`)
+		}
+		var atts map[string]tftypes.Value
+		err := c.As(&atts)
+		if err != nil {
+			return nil, err
+		}
+		vals := make(map[string]string)
+		for k, v := range atts {
+			if k != "type" && k != "status" && k != "reason" {
+				return nil, fmt.Errorf("unsupported attribute %q in \"condition\"", k)
+			}
+			if v.IsNull() {
+				continue
+			}
+			if !v.Type().Is(tftypes.String) {
+				return nil, fmt.Errorf("%q of \"condition\" should be a string", k)
+			}
+			var s string
+			v.As(&s)
+			vals[k] = s
+		}
+		if vals["type"] == "" || vals["status"] == "" {
+			return nil, fmt.Errorf(`"condition" requires both "type" and "status"`)
+		}
+		matchers = append(matchers, ConditionMatcher{vals["type"], vals["status"], vals["reason"]})
+	}
+	return matchers, nil
 }
 
 // FieldMatcher contains a tftypes.AttributePath to a field and a regexp to match on it
 type FieldMatcher struct {
+	field        string
 	path         *tftypes.AttributePath
 	valueMatcher *regexp.Regexp
 }
 
+// ConditionMatcher describes an entry of 'status.conditions' to wait for, found by its type.
+// An empty reason matches any reason.
+type ConditionMatcher struct {
+	conditionType string
+	status        string
+	reason        string
+}
+
+func (c ConditionMatcher) String() string {
+	return fmt.Sprintf("condition %q", c.conditionType)
+}
+
+// match reports whether the condition is met by an API object, along with the observed state of the condition.
+// Conditions written for an older generation of the object never match.
+func (c ConditionMatcher) match(obj map[string]interface{}) (bool, string) {
+	generation, hasGeneration, _ := unstructured.NestedInt64(obj, "metadata", "generation")
+	if hasGeneration {
+		og, ok, _ := unstructured.NestedInt64(obj, "status", "observedGeneration")
+		if ok && og < generation {
+			return false, fmt.Sprintf("(stale, status.observedGeneration %d is behind generation %d)", og, generation)
+		}
+	}
+	cond := statusCondition(obj, c.conditionType)
+	if cond == nil {
+		return false, "(not present)"
+	}
+	status, _ := cond["status"].(string)
+	reason, _ := cond["reason"].(string)
+	observed := fmt.Sprintf("status %q, reason %q", status, reason)
+	if hasGeneration {
+		og, ok, _ := unstructured.NestedInt64(cond, "observedGeneration")
+		if ok && og < generation {
+			return false, fmt.Sprintf("%s (stale, observedGeneration %d is behind generation %d)", observed, og, generation)
+		}
+	}
+	// status is compared case-insensitively, like 'kubectl wait' does
+	return strings.EqualFold(status, c.status) && (c.reason == "" || reason == c.reason), observed
+}
+
 // FieldWaiter will wait for a set of fields to be set,
 // or have a particular value
 type FieldWaiter struct {
-	resource      dynamic.ResourceInterface
+	resource dynamic.ResourceInterface
+	// watchClient watches the resource, it falls back to resource when not set
+	watchClient   dynamic.ResourceInterface
 	resourceName  string
 	resourceType  tftypes.Type
 	fieldMatchers []FieldMatcher
-	logger        hclog.Logger
+	conditions    []ConditionMatcher
+	expression    *ExpressionMatcher
+	// ready enables the readiness check for the kind of the resource, see healthChecks
+	ready bool
+	// pollInterval is the time between two reads of the resource when the API server doesn't support watch
+	pollInterval time.Duration
+	logger       hclog.Logger
 }
 
-// Wait blocks until all of the FieldMatchers configured evaluate to true
+// watchInterrupted is returned by watchResource when the watch failed and should be resumed after a backoff
+type watchInterrupted struct {
+	err error
+}
+
+func (e *watchInterrupted) Error() string {
+	return e.err.Error()
+}
+
+// Wait blocks until all of the FieldMatchers, ConditionMatchers and the expression configured evaluate to true,
+// or returns a WaitTimeoutError when the deadline of the context passes first.
+// It watches the resource for changes and falls back to polling when the API server doesn't support watch.
 func (w *FieldWaiter) Wait(ctx context.Context) error {
 	w.logger.Info("[ApplyResourceChange][Wait] Waiting until ready...\n")
+	lastObserved := make(map[string]string)
+	for _, m := range w.fieldMatchers {
+		lastObserved[m.field] = "(not read yet)"
+	}
+	for _, c := range w.conditions {
+		lastObserved[c.String()] = "(not read yet)"
+	}
+	if w.expression != nil {
+		lastObserved[w.expression.String()] = "(not read yet)"
+	}
+	if w.ready {
+		lastObserved["ready"] = "(not read yet)"
+	}
+	timedOut := func() error {
+		if ctx.Err() == context.DeadlineExceeded {
+			return &WaitTimeoutError{LastObserved: lastObserved}
+		}
+		return ctx.Err()
+	}
+
+	// an empty resourceVersion means the resource has to be read before it can be watched (again)
+	rv := ""
+	backoff := waitWatchBackoffMin
 	for {
-		// NOTE The typed API resource is actually returned in the
-		// event object but I haven't yet figured out how to convert it
-		// to a cty.Value.
-		res, err := w.resource.Get(ctx, w.resourceName, v1.GetOptions{})
+		if rv == "" {
+			res, err := w.resource.Get(ctx, w.resourceName, v1.GetOptions{})
+			if err != nil {
+				if ctx.Err() != nil {
+					return timedOut()
+				}
+				return err
+			}
+			done, err := w.check(res, lastObserved)
+			if err != nil || done {
+				return err
+			}
+			rv = res.GetResourceVersion()
+		}
+
+		prevRV := rv
+		var done bool
+		var err error
+		rv, done, err = w.watchResource(ctx, rv, lastObserved)
+		if done {
+			return nil
+		}
+		if rv != "" && rv != prevRV {
+			// the watch delivered events, so the API server is responsive again
+			backoff = waitWatchBackoffMin
+		}
+		if ctx.Err() != nil {
+			return timedOut()
+		}
+		if errors.IsMethodNotSupported(err) {
+			w.logger.Info("[ApplyResourceChange][Wait]", "watch is not supported by the API server, polling every", w.pollInterval.String())
+			return w.poll(ctx, lastObserved, timedOut)
+		}
+		if wi, ok := err.(*watchInterrupted); ok {
+			w.logger.Warn("[ApplyResourceChange][Wait]", "watch failed, retrying in", backoff.String(), "error", wi.err.Error())
+			select {
+			case <-ctx.Done():
+				return timedOut()
+			case <-time.After(backoff):
+			}
+			backoff *= 2
+			if backoff > waitWatchBackoffMax {
+				backoff = waitWatchBackoffMax
+			}
+			continue
+		}
 		if err != nil {
 			return err
 		}
-		if errors.IsGone(err) {
-			return fmt.Errorf("resource was deleted")
+		// the API server ended the watch, resume it from the last resourceVersion seen
+		backoff = waitWatchBackoffMin
+	}
+}
+
+// watchResource checks the resource on every change, starting after the given resourceVersion.
+// It returns the last resourceVersion seen, which is empty when the watch can't be resumed from it,
+// and a nil error when the watch was closed by the API server.
+func (w *FieldWaiter) watchResource(ctx context.Context, rv string, lastObserved map[string]string) (string, bool, error) {
+	wc := w.watchClient
+	if wc == nil {
+		wc = w.resource
+	}
+	opts := v1.ListOptions{
+		FieldSelector:       fields.OneTermEqualSelector("metadata.name", w.resourceName).String(),
+		ResourceVersion:     rv,
+		AllowWatchBookmarks: true,
+	}
+	// have the API server end the watch when the wait is over, instead of leaving it to its default timeout
+	if deadline, ok := ctx.Deadline(); ok {
+		secs := int64(math.Ceil(time.Until(deadline).Seconds()))
+		if secs < 1 {
+			secs = 1
+		}
+		opts.TimeoutSeconds = &secs
+	}
+	watcher, err := wc.Watch(ctx, opts)
+	if err != nil {
+		if errors.IsResourceExpired(err) || errors.IsGone(err) {
+			// the resourceVersion is too old to resume from
+			return "", false, nil
 		}
-		resObj := res.Object
-		meta := resObj["metadata"].(map[string]interface{})
-		delete(meta, "managedFields")
+		if isWatchTimeout(err) && ctx.Err() == nil {
+			// a timeout isn't a failure of the API server, the watch is resumed right away
+			w.logger.Debug("[ApplyResourceChange][Wait]", "watch timed out, resuming", err.Error())
+			return rv, false, nil
+		}
+		if errors.IsMethodNotSupported(err) || ctx.Err() != nil {
+			return rv, false, err
+		}
+		return rv, false, &watchInterrupted{err}
+	}
+	defer watcher.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return rv, false, ctx.Err()
+		case ev, ok := <-watcher.ResultChan():
+			if !ok {
+				return rv, false, nil
+			}
+			switch ev.Type {
+			case watch.Added, watch.Modified:
+				res, ok := ev.Object.(*unstructured.Unstructured)
+				if !ok {
+					return rv, false, &watchInterrupted{fmt.Errorf("unexpected object in watch event: %T", ev.Object)}
+				}
+				rv = res.GetResourceVersion()
+				done, err := w.check(res, lastObserved)
+				if err != nil || done {
+					return rv, done, err
+				}
+			case watch.Bookmark:
+				if res, ok := ev.Object.(*unstructured.Unstructured); ok {
+					rv = res.GetResourceVersion()
+				}
+			case watch.Deleted:
+				return rv, false, fmt.Errorf("resource was deleted")
+			case watch.Error:
+				err := errors.FromObject(ev.Object)
+				if errors.IsResourceExpired(err) || errors.IsGone(err) {
+					// the resourceVersion is too old to resume from
+					return "", false, nil
+				}
+				if isWatchTimeout(err) {
+					return rv, false, nil
+				}
+				return rv, false, &watchInterrupted{err}
+			}
+		}
+	}
+}
 
-		w.logger.Trace("[ApplyResourceChange][Wait]", "API Response", resObj)
+// isWatchTimeout reports whether a watch ended because a timeout expired, on the client or on the API server
+func isWatchTimeout(err error) bool {
+	return errors.IsTimeout(err) || errors.IsServerTimeout(err) || utilnet.IsTimeout(err)
+}
 
-		obj, err := payload.ToTFValue(resObj, w.resourceType, tftypes.NewAttributePath())
+// poll checks the resource every pollInterval
+func (w *FieldWaiter) poll(ctx context.Context, lastObserved map[string]string, timedOut func() error) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return timedOut()
+		case <-time.After(w.pollInterval):
+		}
+		res, err := w.resource.Get(ctx, w.resourceName, v1.GetOptions{})
 		if err != nil {
+			if ctx.Err() != nil {
+				return timedOut()
+			}
 			return err
 		}
+		done, err := w.check(res, lastObserved)
+		if err != nil || done {
+			return err
+		}
+	}
+}
 
-		done, err := func(obj tftypes.Value) (bool, error) {
-			for _, m := range w.fieldMatchers {
-				vi, rp, err := tftypes.WalkAttributePath(obj, m.path)
-				if err != nil {
-					return false, err
-				}
-				if len(rp.Steps()) > 0 {
-					return false, fmt.Errorf("attribute not present at path '%s'", m.path.String())
-				}
+// check evaluates all conditions against a copy of the resource read from the API server
+// and records the values it finds in lastObserved
+func (w *FieldWaiter) check(res *unstructured.Unstructured, lastObserved map[string]string) (bool, error) {
+	res = res.DeepCopy()
+	resObj := res.Object
+	if meta, ok := resObj["metadata"].(map[string]interface{}); ok {
+		delete(meta, "managedFields")
+	}
 
-				var s string
-				v := vi.(tftypes.Value)
-				switch {
-				case v.Type().Is(tftypes.String):
-					v.As(&s)
-				case v.Type().Is(tftypes.Bool):
-					var vb bool
-					v.As(&vb)
-					s = fmt.Sprintf("%t", vb)
-				case v.Type().Is(tftypes.Number):
-					var f big.Float
-					v.As(&f)
-					if f.IsInt() {
-						i, _ := f.Int64()
-						s = fmt.Sprintf("%d", i)
-					} else {
-						i, _ := f.Float64()
-						s = fmt.Sprintf("%f", i)
-					}
-				default:
-					return true, fmt.Errorf("wait_for: cannot match on type %q", v.Type().String())
-				}
+	w.logger.Trace("[ApplyResourceChange][Wait]", "API Response", resObj)
 
-				if !m.valueMatcher.Match([]byte(s)) {
-					return false, nil
-				}
+	obj, err := payload.ToTFValue(resObj, w.resourceType, tftypes.NewAttributePath())
+	if err != nil {
+		return false, err
+	}
+
+	// read all fields, even after a mismatch, so their last values can be reported on timeout
+	done := true
+	for _, m := range w.fieldMatchers {
+		vi, rp, err := tftypes.WalkAttributePath(obj, m.path)
+		if err != nil || len(rp.Steps()) > 0 {
+			lastObserved[m.field] = "(not present)"
+			done = false
+			continue
+		}
+
+		var s string
+		v := vi.(tftypes.Value)
+		switch {
+		case v.Type().Is(tftypes.String):
+			v.As(&s)
+		case v.Type().Is(tftypes.Bool):
+			var vb bool
+			v.As(&vb)
+			s = fmt.Sprintf("%t", vb)
+		case v.Type().Is(tftypes.Number):
+			var f big.Float
+			v.As(&f)
+			if f.IsInt() {
+				i, _ := f.Int64()
+				s = fmt.Sprintf("%d", i)
+			} else {
+				i, _ := f.Float64()
+				s = fmt.Sprintf("%f", i)
 			}
+		default:
+			return false, fmt.Errorf("wait_for: cannot match on type %q", v.Type().String())
+		}
+		lastObserved[m.field] = fmt.Sprintf("%q", s)
 
-			return true, nil
-		}(obj)
+		if !m.valueMatcher.Match([]byte(s)) {
+			done = false
+		}
+	}
 
-		if done {
-			return err
+	for _, c := range w.conditions {
+		met, observed := c.match(resObj)
+		lastObserved[c.String()] = observed
+		if !met {
+			done = false
+		}
+	}
+	if w.expression != nil {
+		met, observed := w.expression.match(resObj)
+		lastObserved[w.expression.String()] = observed
+		if !met {
+			done = false
+		}
+	}
+	if w.ready {
+		hc, err := healthCheckFor(res.GetAPIVersion(), res.GetKind())
+		if err != nil {
+			return false, err
+		}
+		ready, observed, err := hc(resObj)
+		if err != nil {
+			return false, fmt.Errorf("resource will not become ready: %s", err)
+		}
+		lastObserved["ready"] = observed
+		if !ready {
+			done = false
 		}
 	}
+	return done, nil
 }
 
 // NoopWaiter is a placeholder for when there is nothing to wait on
@@ -185,6 +620,33 @@ func (w *NoopWaiter) Wait(_ context.Context) error {
 	return nil
 }
 
+// waitForDeletion reads a deleted resource until the API server no longer returns it, which can take
+// a while when finalizers are set. When ctx expires first, the error names the finalizers that are left.
+func waitForDeletion(ctx context.Context, rs dynamic.ResourceInterface, rname string, pollInterval time.Duration) error {
+	var last *unstructured.Unstructured
+	for {
+		res, err := rs.Get(ctx, rname, v1.GetOptions{})
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		if err == nil {
+			last = res
+		} else if ctx.Err() == nil {
+			return err
+		}
+		if ctx.Err() != nil {
+			if last != nil && len(last.GetFinalizers()) > 0 {
+				return fmt.Errorf("resource is still being deleted, waiting for finalizers: %s: %w", strings.Join(last.GetFinalizers(), ", "), ctx.Err())
+			}
+			return fmt.Errorf("resource is still being deleted: %w", ctx.Err())
+		}
+		select {
+		case <-ctx.Done():
+		case <-time.After(pollInterval):
+		}
+	}
+}
+
 // FieldPathToTftypesPath takes a string representation of
 // a path to a field in dot/square bracket notation
 // and returns a tftypes.AttributePath
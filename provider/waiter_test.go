@@ -0,0 +1,490 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"reflect"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/dynamic"
+)
+
+func TestParseWaitForBlock(t *testing.T) {
+	str := func(s string) tftypes.Value { return tftypes.NewValue(tftypes.String, s) }
+	obj := func(atts map[string]tftypes.Value) tftypes.Value {
+		types := make(map[string]tftypes.Type)
+		for k, v := range atts {
+			types[k] = v.Type()
+		}
+		return tftypes.NewValue(tftypes.Object{AttributeTypes: types}, atts)
+	}
+	ready := obj(map[string]tftypes.Value{"type": str("Ready"), "status": str("True")})
+	available := obj(map[string]tftypes.Value{"type": str("Available"), "status": str("True"), "reason": str("MinimumReplicasAvailable")})
+
+	samples := []struct {
+		in         tftypes.Value
+		fields     int
+		conditions []ConditionMatcher
+		expression bool
+		ready      bool
+		err        bool
+		// attribute is the attribute of 'wait_for' the error is reported on
+		attribute string
+	}{
+		{
+			in: tftypes.NewValue(tftypes.DynamicPseudoType, nil),
+		},
+		{
+			in: tftypes.NewValue(tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+				"fields": tftypes.Map{AttributeType: tftypes.String},
+			}}, map[string]tftypes.Value{
+				"fields": tftypes.NewValue(tftypes.Map{AttributeType: tftypes.String}, map[string]tftypes.Value{
+					"status.phase": str("Running"),
+				}),
+			}),
+			fields: 1,
+		},
+		{
+			in: obj(map[string]tftypes.Value{
+				"fields":    obj(map[string]tftypes.Value{"status.podIP": str("*")}),
+				"condition": ready,
+			}),
+			fields:     1,
+			conditions: []ConditionMatcher{{"Ready", "True", ""}},
+		},
+		{
+			in: obj(map[string]tftypes.Value{
+				"condition": tftypes.NewValue(tftypes.Tuple{ElementTypes: []tftypes.Type{ready.Type(), available.Type()}}, []tftypes.Value{ready, available}),
+			}),
+			conditions: []ConditionMatcher{{"Ready", "True", ""}, {"Available", "True", "MinimumReplicasAvailable"}},
+		},
+		{
+			// missing status
+			in:        obj(map[string]tftypes.Value{"condition": obj(map[string]tftypes.Value{"type": str("Ready")})}),
+			err:       true,
+			attribute: "condition",
+		},
+		{
+			in:  obj(map[string]tftypes.Value{"condition": obj(map[string]tftypes.Value{"type": str("Ready"), "status": str("True"), "message": str("ok")})}),
+			err: true,
+		},
+		{
+			in:  obj(map[string]tftypes.Value{"condition": str("Ready")}),
+			err: true,
+		},
+		{
+			in:        obj(map[string]tftypes.Value{"fields": obj(map[string]tftypes.Value{"spec.replicas": tftypes.NewValue(tftypes.Number, 3)})}),
+			err:       true,
+			attribute: "fields",
+		},
+		{
+			in:    obj(map[string]tftypes.Value{"ready": tftypes.NewValue(tftypes.Bool, true)}),
+			ready: true,
+		},
+		{
+			in:  obj(map[string]tftypes.Value{"ready": str("yes")}),
+			err: true,
+		},
+		{
+			in:  obj(map[string]tftypes.Value{"rollout": tftypes.NewValue(tftypes.Bool, true)}),
+			err: true,
+		},
+		{
+			in: obj(map[string]tftypes.Value{
+				"expression": str("{.status.readyReplicas} == {.spec.replicas}"),
+				"condition":  ready,
+			}),
+			conditions: []ConditionMatcher{{"Ready", "True", ""}},
+			expression: true,
+		},
+		{
+			in:        obj(map[string]tftypes.Value{"expression": str("{.status.readyReplicas")}),
+			err:       true,
+			attribute: "expression",
+		},
+		{
+			in:        obj(map[string]tftypes.Value{"expression": tftypes.NewValue(tftypes.Bool, true)}),
+			err:       true,
+			attribute: "expression",
+		},
+	}
+
+	for i, s := range samples {
+		t.Run(fmt.Sprintf("sample%d", i+1), func(t *testing.T) {
+			wf, err := parseWaitForBlock(s.in)
+			if s.err {
+				if err == nil {
+					t.Fatal("expected error")
+				}
+				if s.attribute != "" {
+					ae, ok := err.(*waitForAttributeError)
+					if !ok || ae.attribute != s.attribute {
+						t.Fatalf("expected error on attribute %q, got %#v", s.attribute, err)
+					}
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if (wf.expression != nil) != s.expression {
+				t.Fatalf("expected expression to be set: %v", s.expression)
+			}
+			if len(wf.fields) != s.fields {
+				t.Fatalf("expected %d field matchers, got %d", s.fields, len(wf.fields))
+			}
+			if len(wf.conditions) != len(s.conditions) {
+				t.Fatalf("expected %d condition matchers, got %d", len(s.conditions), len(wf.conditions))
+			}
+			if wf.ready != s.ready {
+				t.Fatalf("expected ready to be %v, got %v", s.ready, wf.ready)
+			}
+			for j, c := range wf.conditions {
+				if c != s.conditions[j] {
+					t.Fatalf("expected condition %#v, got %#v", s.conditions[j], c)
+				}
+			}
+		})
+	}
+}
+
+func TestConditionMatcher(t *testing.T) {
+	object := func(generation int64, status map[string]interface{}) map[string]interface{} {
+		return map[string]interface{}{
+			"metadata": map[string]interface{}{"name": "web", "generation": generation},
+			"status":   status,
+		}
+	}
+	conditions := func(conds ...map[string]interface{}) []interface{} {
+		l := []interface{}{}
+		for _, c := range conds {
+			l = append(l, c)
+		}
+		return l
+	}
+
+	samples := []struct {
+		matcher ConditionMatcher
+		obj     map[string]interface{}
+		met     bool
+	}{
+		{
+			matcher: ConditionMatcher{"Ready", "True", ""},
+			obj: object(1, map[string]interface{}{"conditions": conditions(
+				map[string]interface{}{"type": "Initialized", "status": "True"},
+				map[string]interface{}{"type": "Ready", "status": "True", "reason": "PodReady"},
+			)}),
+			met: true,
+		},
+		{
+			matcher: ConditionMatcher{"Ready", "true", ""},
+			obj:     object(1, map[string]interface{}{"conditions": conditions(map[string]interface{}{"type": "Ready", "status": "True"})}),
+			met:     true,
+		},
+		{
+			matcher: ConditionMatcher{"Ready", "True", ""},
+			obj:     object(1, map[string]interface{}{"conditions": conditions(map[string]interface{}{"type": "Ready", "status": "False"})}),
+		},
+		{
+			matcher: ConditionMatcher{"Ready", "True", ""},
+			obj:     object(1, map[string]interface{}{}),
+		},
+		{
+			matcher: ConditionMatcher{"Available", "True", "MinimumReplicasAvailable"},
+			obj:     object(1, map[string]interface{}{"conditions": conditions(map[string]interface{}{"type": "Available", "status": "True", "reason": "NewReplicaSetAvailable"})}),
+		},
+		{
+			// condition written for an older generation
+			matcher: ConditionMatcher{"Ready", "True", ""},
+			obj:     object(3, map[string]interface{}{"conditions": conditions(map[string]interface{}{"type": "Ready", "status": "True", "observedGeneration": int64(2)})}),
+		},
+		{
+			// status written for an older generation
+			matcher: ConditionMatcher{"Available", "True", ""},
+			obj: object(3, map[string]interface{}{
+				"observedGeneration": int64(2),
+				"conditions":         conditions(map[string]interface{}{"type": "Available", "status": "True"}),
+			}),
+		},
+		{
+			matcher: ConditionMatcher{"Available", "True", ""},
+			obj: object(3, map[string]interface{}{
+				"observedGeneration": int64(3),
+				"conditions":         conditions(map[string]interface{}{"type": "Available", "status": "True", "observedGeneration": int64(3)}),
+			}),
+			met: true,
+		},
+	}
+
+	for i, s := range samples {
+		t.Run(fmt.Sprintf("sample%d", i+1), func(t *testing.T) {
+			met, observed := s.matcher.match(s.obj)
+			if met != s.met {
+				t.Fatalf("expected condition met to be %v, got %v (%s)", s.met, met, observed)
+			}
+		})
+	}
+}
+
+// watchedResource serves GETs from a fixed object, or from next after the first read when set,
+// and replays one event sequence per call to Watch
+type watchedResource struct {
+	dynamic.ResourceInterface
+	obj     map[string]interface{}
+	next    map[string]interface{}
+	watches []func(*watch.FakeWatcher) error
+	gets    int
+	// resumedFrom records the resourceVersion of every call to Watch
+	resumedFrom []string
+	// timeoutSeconds records the timeout of the last call to Watch
+	timeoutSeconds *int64
+}
+
+func (r *watchedResource) Get(ctx context.Context, name string, options metav1.GetOptions, subresources ...string) (*unstructured.Unstructured, error) {
+	r.gets++
+	if r.gets > 1 && r.next != nil {
+		return &unstructured.Unstructured{Object: r.next}, nil
+	}
+	return &unstructured.Unstructured{Object: r.obj}, nil
+}
+
+func (r *watchedResource) Watch(ctx context.Context, opts metav1.ListOptions) (watch.Interface, error) {
+	r.resumedFrom = append(r.resumedFrom, opts.ResourceVersion)
+	r.timeoutSeconds = opts.TimeoutSeconds
+	if len(r.watches) == 0 {
+		// no more events, wait for the context to expire
+		return watch.NewFake(), nil
+	}
+	fw := watch.NewFakeWithChanSize(10, false)
+	next := r.watches[0]
+	r.watches = r.watches[1:]
+	if err := next(fw); err != nil {
+		return nil, err
+	}
+	return fw, nil
+}
+
+func TestFieldWaiterWatch(t *testing.T) {
+	pod := func(rv, phase string) *unstructured.Unstructured {
+		return &unstructured.Unstructured{Object: map[string]interface{}{
+			"metadata": map[string]interface{}{"name": "web", "resourceVersion": rv},
+			"status":   map[string]interface{}{"phase": phase},
+		}}
+	}
+	expired := &apierrors.NewResourceExpired("too old resource version").ErrStatus
+
+	samples := []struct {
+		watches     []func(*watch.FakeWatcher) error
+		next        *unstructured.Unstructured
+		gets        int
+		resumedFrom []string
+		err         bool
+	}{
+		{
+			watches: []func(*watch.FakeWatcher) error{
+				func(fw *watch.FakeWatcher) error {
+					fw.Modify(pod("2", "Pending"))
+					fw.Modify(pod("3", "Running"))
+					return nil
+				},
+			},
+			gets:        1,
+			resumedFrom: []string{"1"},
+		},
+		{
+			// closed by the API server, resumed from the last resourceVersion seen
+			watches: []func(*watch.FakeWatcher) error{
+				func(fw *watch.FakeWatcher) error {
+					fw.Modify(pod("2", "Pending"))
+					fw.Stop()
+					return nil
+				},
+				func(fw *watch.FakeWatcher) error {
+					fw.Modify(pod("3", "Running"))
+					return nil
+				},
+			},
+			gets:        1,
+			resumedFrom: []string{"1", "2"},
+		},
+		{
+			// resourceVersion expired, the resource is read again
+			watches: []func(*watch.FakeWatcher) error{
+				func(fw *watch.FakeWatcher) error {
+					fw.Error(expired)
+					return nil
+				},
+				func(fw *watch.FakeWatcher) error {
+					fw.Modify(pod("5", "Running"))
+					return nil
+				},
+			},
+			gets:        2,
+			resumedFrom: []string{"1", "1"},
+		},
+		{
+			// resourceVersion expired before the watch started, the resource is read again
+			watches: []func(*watch.FakeWatcher) error{
+				func(fw *watch.FakeWatcher) error {
+					return apierrors.NewResourceExpired("too old resource version")
+				},
+				func(fw *watch.FakeWatcher) error {
+					fw.Modify(pod("5", "Running"))
+					return nil
+				},
+			},
+			gets:        2,
+			resumedFrom: []string{"1", "1"},
+		},
+		{
+			watches: []func(*watch.FakeWatcher) error{
+				func(fw *watch.FakeWatcher) error {
+					return apierrors.NewGone("too old resource version")
+				},
+				func(fw *watch.FakeWatcher) error {
+					fw.Modify(pod("5", "Running"))
+					return nil
+				},
+			},
+			gets:        2,
+			resumedFrom: []string{"1", "1"},
+		},
+		{
+			// timeouts aren't failures, the watch is resumed without backoff
+			watches: []func(*watch.FakeWatcher) error{
+				func(fw *watch.FakeWatcher) error {
+					return &url.Error{Op: "Get", URL: "https://kubernetes.example.com/api/v1/pods", Err: os.ErrDeadlineExceeded}
+				},
+				func(fw *watch.FakeWatcher) error {
+					fw.Error(&apierrors.NewTimeoutError("watch timed out", 0).ErrStatus)
+					return nil
+				},
+				func(fw *watch.FakeWatcher) error {
+					fw.Modify(pod("2", "Running"))
+					return nil
+				},
+			},
+			gets:        1,
+			resumedFrom: []string{"1", "1", "1"},
+		},
+		{
+			watches: []func(*watch.FakeWatcher) error{
+				func(fw *watch.FakeWatcher) error {
+					fw.Delete(pod("2", "Pending"))
+					return nil
+				},
+			},
+			gets:        1,
+			resumedFrom: []string{"1"},
+			err:         true,
+		},
+		{
+			// watch not supported, falls back to polling
+			watches: []func(*watch.FakeWatcher) error{
+				func(fw *watch.FakeWatcher) error {
+					return apierrors.NewMethodNotSupported(schema.GroupResource{Resource: "pods"}, "watch")
+				},
+			},
+			next:        pod("2", "Running"),
+			gets:        2,
+			resumedFrom: []string{"1"},
+		},
+	}
+
+	rt := tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+		"metadata": tftypes.Object{AttributeTypes: map[string]tftypes.Type{"name": tftypes.String, "resourceVersion": tftypes.String}},
+		"status":   tftypes.Object{AttributeTypes: map[string]tftypes.Type{"phase": tftypes.String}},
+	}}
+	for i, s := range samples {
+		t.Run(fmt.Sprintf("sample%d", i+1), func(t *testing.T) {
+			rs := &watchedResource{obj: pod("1", "Pending").Object, watches: s.watches}
+			if s.next != nil {
+				rs.next = s.next.Object
+			}
+			w := &FieldWaiter{
+				resource:     rs,
+				resourceName: "web",
+				resourceType: rt,
+				fieldMatchers: []FieldMatcher{
+					{"status.phase", tftypes.NewAttributePath().WithAttributeName("status").WithAttributeName("phase"), regexp.MustCompile("^Running$")},
+				},
+				pollInterval: 10 * time.Millisecond,
+				logger:       hclog.NewNullLogger(),
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+			err := w.Wait(ctx)
+			if s.err {
+				if err == nil {
+					t.Fatal("expected error")
+				}
+			} else if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if rs.gets != s.gets {
+				t.Fatalf("expected %d reads of the resource, got %d", s.gets, rs.gets)
+			}
+			if !reflect.DeepEqual(rs.resumedFrom, s.resumedFrom) {
+				t.Fatalf("expected watches from resourceVersions %v, got %v", s.resumedFrom, rs.resumedFrom)
+			}
+		})
+	}
+}
+
+func TestFieldWaiterWatchClient(t *testing.T) {
+	rt := tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+		"metadata": tftypes.Object{AttributeTypes: map[string]tftypes.Type{"name": tftypes.String, "resourceVersion": tftypes.String}},
+		"status":   tftypes.Object{AttributeTypes: map[string]tftypes.Type{"phase": tftypes.String}},
+	}}
+	// reads go through the resource, which doesn't support watch - watches have their own client
+	rs := &staticResource{obj: map[string]interface{}{
+		"metadata": map[string]interface{}{"name": "web", "resourceVersion": "1"},
+		"status":   map[string]interface{}{"phase": "Pending"},
+	}}
+	wc := &watchedResource{watches: []func(*watch.FakeWatcher) error{
+		func(fw *watch.FakeWatcher) error {
+			fw.Modify(&unstructured.Unstructured{Object: map[string]interface{}{
+				"metadata": map[string]interface{}{"name": "web", "resourceVersion": "2"},
+				"status":   map[string]interface{}{"phase": "Running"},
+			}})
+			return nil
+		},
+	}}
+	w := &FieldWaiter{
+		resource:     rs,
+		watchClient:  wc,
+		resourceName: "web",
+		resourceType: rt,
+		fieldMatchers: []FieldMatcher{
+			{"status.phase", tftypes.NewAttributePath().WithAttributeName("status").WithAttributeName("phase"), regexp.MustCompile("^Running$")},
+		},
+		pollInterval: 10 * time.Millisecond,
+		logger:       hclog.NewNullLogger(),
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 90*time.Second)
+	defer cancel()
+	err := w.Wait(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if !reflect.DeepEqual(wc.resumedFrom, []string{"1"}) {
+		t.Fatalf("expected a watch from resourceVersion 1, got %v", wc.resumedFrom)
+	}
+	// the API server ends the watch once the wait is over
+	if wc.timeoutSeconds == nil || *wc.timeoutSeconds < 89 || *wc.timeoutSeconds > 90 {
+		t.Fatalf("expected the watch to time out with the wait, got %v", wc.timeoutSeconds)
+	}
+}
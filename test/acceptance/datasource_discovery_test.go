@@ -0,0 +1,34 @@
+// +build acceptance
+
+package acceptance
+
+import (
+	"testing"
+
+	tfstatehelper "github.com/hashicorp/terraform-provider-kubernetes-alpha/test/helper/state"
+)
+
+func TestDataSourceKubernetesDiscovery(t *testing.T) {
+	tf := tfhelper.RequireNewWorkingDir(t)
+	tf.SetReattachInfo(reattachInfo)
+	defer func() {
+		tf.RequireDestroy(t)
+		tf.Close()
+	}()
+
+	tfconfig := loadTerraformConfig(t, "DataSource/discovery.tf", TFVARS{})
+	tf.RequireSetConfig(t, tfconfig)
+	tf.RequireInit(t)
+	tf.RequireApply(t)
+
+	tfstate := tfstatehelper.NewHelper(tf.RequireState(t))
+	tfstate.AssertAttributeNotEmpty(t, "data.kubernetes_discovery.test.server_version.git_version")
+	tfstate.AssertAttributeNotEmpty(t, "data.kubernetes_discovery.test.api_versions")
+	tfstate.AssertAttributeNotEmpty(t, "data.kubernetes_discovery.test.resources")
+	tfstate.AssertAttributeValues(t, tfstatehelper.AttributeValues{
+		"data.kubernetes_discovery.test.api_groups.apps.preferred_version": "v1",
+	})
+	if !tfstate.Values.Outputs["has_apps_v1"].Value.(bool) {
+		t.Fatal("expected apps/v1 to be among the served API versions")
+	}
+}
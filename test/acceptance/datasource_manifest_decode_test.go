@@ -0,0 +1,42 @@
+// +build acceptance
+
+package acceptance
+
+import (
+	"fmt"
+	"testing"
+
+	tfstatehelper "github.com/hashicorp/terraform-provider-kubernetes-alpha/test/helper/state"
+)
+
+func TestDataSourceKubernetesManifestDecode(t *testing.T) {
+	name := randName()
+	namespace := randName()
+
+	tf := tfhelper.RequireNewWorkingDir(t)
+	tf.SetReattachInfo(reattachInfo)
+	defer func() {
+		tf.RequireDestroy(t)
+		tf.Close()
+	}()
+
+	tfvars := TFVARS{
+		"name":      name,
+		"namespace": namespace,
+	}
+	tfconfig := loadTerraformConfig(t, "DataSource/manifest_decode.tf", tfvars)
+	tf.RequireSetConfig(t, tfconfig)
+	tf.RequireInit(t)
+	tf.RequireApply(t)
+
+	cmKey := fmt.Sprintf("ConfigMap/%s/%s", namespace, name)
+	nsKey := fmt.Sprintf("Namespace/%s", name)
+
+	tfstate := tfstatehelper.NewHelper(tf.RequireState(t))
+	tfstate.AssertAttributeLen(t, "data.kubernetes_manifest_decode.test.manifests", 2)
+	tfstate.AssertAttributeValues(t, tfstatehelper.AttributeValues{
+		"data.kubernetes_manifest_decode.test.manifests." + cmKey + ".kind":     "ConfigMap",
+		"data.kubernetes_manifest_decode.test.manifests." + cmKey + ".data.foo": "bar",
+		"data.kubernetes_manifest_decode.test.manifests." + nsKey + ".kind":     "Namespace",
+	})
+}
@@ -0,0 +1,43 @@
+// +build acceptance
+
+package acceptance
+
+import (
+	"testing"
+
+	tfstatehelper "github.com/hashicorp/terraform-provider-kubernetes-alpha/test/helper/state"
+)
+
+func TestDataSourceKubernetesResource_ConfigMap(t *testing.T) {
+	name := randName()
+	namespace := randName()
+
+	tf := tfhelper.RequireNewWorkingDir(t)
+	tf.SetReattachInfo(reattachInfo)
+	defer func() {
+		tf.RequireDestroy(t)
+		tf.Close()
+		k8shelper.AssertNamespacedResourceDoesNotExist(t, "v1", "configmaps", namespace, name)
+	}()
+
+	k8shelper.CreateNamespace(t, namespace)
+	defer k8shelper.DeleteNamespace(t, namespace)
+
+	tfvars := TFVARS{
+		"namespace": namespace,
+		"name":      name,
+	}
+	tfconfig := loadTerraformConfig(t, "DataSource/resource_configmap.tf", tfvars)
+	tf.RequireSetConfig(t, tfconfig)
+	tf.RequireInit(t)
+	tf.RequireApply(t)
+
+	tfstate := tfstatehelper.NewHelper(tf.RequireState(t))
+	tfstate.AssertAttributeValues(t, tfstatehelper.AttributeValues{
+		"data.kubernetes_resource.test.object.metadata.namespace": namespace,
+		"data.kubernetes_resource.test.object.metadata.name":      name,
+		"data.kubernetes_resource.test.object.data.foo":           "bar",
+	})
+	tfstate.AssertAttributeNotEmpty(t, "data.kubernetes_resource.test.object.metadata.uid")
+	tfstate.AssertAttributeEmpty(t, "data.kubernetes_resource.missing.object")
+}
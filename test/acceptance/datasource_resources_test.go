@@ -0,0 +1,41 @@
+// +build acceptance
+
+package acceptance
+
+import (
+	"testing"
+
+	tfstatehelper "github.com/hashicorp/terraform-provider-kubernetes-alpha/test/helper/state"
+)
+
+func TestDataSourceKubernetesResources_ConfigMaps(t *testing.T) {
+	name := randName()
+	namespace := randName()
+
+	tf := tfhelper.RequireNewWorkingDir(t)
+	tf.SetReattachInfo(reattachInfo)
+	defer func() {
+		tf.RequireDestroy(t)
+		tf.Close()
+	}()
+
+	k8shelper.CreateNamespace(t, namespace)
+	defer k8shelper.DeleteNamespace(t, namespace)
+
+	tfvars := TFVARS{
+		"namespace": namespace,
+		"name":      name,
+	}
+	tfconfig := loadTerraformConfig(t, "DataSource/resources_configmaps.tf", tfvars)
+	tf.RequireSetConfig(t, tfconfig)
+	tf.RequireInit(t)
+	tf.RequireApply(t)
+
+	tfstate := tfstatehelper.NewHelper(tf.RequireState(t))
+	tfstate.AssertAttributeLen(t, "data.kubernetes_resources.test.objects", 2)
+	tfstate.AssertAttributeValues(t, tfstatehelper.AttributeValues{
+		"data.kubernetes_resources.test.objects.0.metadata.labels.selected": "yes",
+		"data.kubernetes_resources.test.objects.1.metadata.labels.selected": "yes",
+	})
+	tfstate.AssertAttributeLen(t, "data.kubernetes_resources.limited.objects", 1)
+}
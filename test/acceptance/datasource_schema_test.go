@@ -0,0 +1,37 @@
+// +build acceptance
+
+package acceptance
+
+import (
+	"testing"
+
+	tfstatehelper "github.com/hashicorp/terraform-provider-kubernetes-alpha/test/helper/state"
+)
+
+func TestDataSourceKubernetesSchema_ConfigMap(t *testing.T) {
+	tf := tfhelper.RequireNewWorkingDir(t)
+	tf.SetReattachInfo(reattachInfo)
+	defer func() {
+		tf.RequireDestroy(t)
+		tf.Close()
+	}()
+
+	tfconfig := loadTerraformConfig(t, "DataSource/schema.tf", TFVARS{})
+	tf.RequireSetConfig(t, tfconfig)
+	tf.RequireInit(t)
+	tf.RequireApply(t)
+
+	tfstate := tfstatehelper.NewHelper(tf.RequireState(t))
+	tfstate.AssertAttributeNotEmpty(t, "data.kubernetes_schema.test.type_json")
+
+	ct, ok := tfstate.Values.Outputs["configmap_type"].Value.([]interface{})
+	if !ok || len(ct) != 2 || ct[0] != "object" {
+		t.Fatalf("expected an object type, got: %#v", tfstate.Values.Outputs["configmap_type"].Value)
+	}
+	atts := ct[1].(map[string]interface{})
+	for _, k := range []string{"apiVersion", "kind", "metadata", "data", "binaryData"} {
+		if _, ok := atts[k]; !ok {
+			t.Errorf("expected attribute %q in ConfigMap type", k)
+		}
+	}
+}
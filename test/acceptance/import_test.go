@@ -0,0 +1,58 @@
+// +build acceptance
+
+package acceptance
+
+import (
+	"fmt"
+	"testing"
+
+	tfstatehelper "github.com/hashicorp/terraform-provider-kubernetes-alpha/test/helper/state"
+)
+
+func TestKubernetesManifest_Import(t *testing.T) {
+	name := randName()
+	namespace := randName()
+
+	k8shelper.CreateNamespace(t, namespace)
+	defer k8shelper.DeleteNamespace(t, namespace)
+
+	tfvars := TFVARS{
+		"namespace": namespace,
+		"name":      name,
+	}
+	tfconfig := loadTerraformConfig(t, "Import/import_configmap.tf", tfvars)
+
+	// create the resource out-of-band, using a separate working directory
+	tfsrc := tfhelper.RequireNewWorkingDir(t)
+	tfsrc.SetReattachInfo(reattachInfo)
+	defer func() {
+		tfsrc.RequireDestroy(t)
+		tfsrc.Close()
+		k8shelper.AssertNamespacedResourceDoesNotExist(t, "v1", "configmaps", namespace, name)
+	}()
+	tfsrc.RequireSetConfig(t, tfconfig)
+	tfsrc.RequireInit(t)
+	tfsrc.RequireApply(t)
+
+	k8shelper.AssertNamespacedResourceExists(t, "v1", "configmaps", namespace, name)
+
+	tf := tfhelper.RequireNewWorkingDir(t)
+	tf.SetReattachInfo(reattachInfo)
+	defer tf.Close()
+	tf.RequireSetConfig(t, tfconfig)
+	tf.RequireInit(t)
+
+	id := fmt.Sprintf("apiVersion=v1,kind=ConfigMap,namespace=%s,name=%s", namespace, name)
+	tf.RequireImport(t, "kubernetes_manifest.test", id)
+
+	tfstate := tfstatehelper.NewHelper(tf.RequireState(t))
+	tfstate.AssertAttributeValues(t, tfstatehelper.AttributeValues{
+		"kubernetes_manifest.test.manifest.metadata.namespace": namespace,
+		"kubernetes_manifest.test.manifest.metadata.name":      name,
+		"kubernetes_manifest.test.manifest.data.foo":           "bar",
+		"kubernetes_manifest.test.object.metadata.namespace":   namespace,
+		"kubernetes_manifest.test.object.metadata.name":        name,
+		"kubernetes_manifest.test.object.data.foo":             "bar",
+	})
+	tfstate.AssertAttributeDoesNotExist(t, "kubernetes_manifest.test.manifest.metadata.uid")
+}
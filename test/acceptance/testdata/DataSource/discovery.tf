@@ -0,0 +1,10 @@
+provider "kubernetes-alpha" {
+}
+
+data "kubernetes_discovery" "test" {
+  provider = kubernetes-alpha
+}
+
+output "has_apps_v1" {
+  value = contains(data.kubernetes_discovery.test.api_versions, "apps/v1")
+}
@@ -0,0 +1,24 @@
+provider "kubernetes-alpha" {
+}
+
+data "kubernetes_manifest_decode" "test" {
+  provider = kubernetes-alpha
+
+  content = <<-EOT
+    apiVersion: v1
+    kind: ConfigMap
+    metadata:
+      name: ${var.name}
+      namespace: ${var.namespace}
+    data:
+      foo: bar
+    ---
+    apiVersion: v1
+    kind: List
+    items:
+    - apiVersion: v1
+      kind: Namespace
+      metadata:
+        name: ${var.name}
+  EOT
+}
@@ -0,0 +1,36 @@
+provider "kubernetes-alpha" {
+}
+
+resource "kubernetes_manifest" "test" {
+  provider = kubernetes-alpha
+
+  manifest = {
+    apiVersion = "v1"
+    kind       = "ConfigMap"
+    metadata = {
+      name      = var.name
+      namespace = var.namespace
+    }
+    data = {
+      foo = "bar"
+    }
+  }
+}
+
+data "kubernetes_resource" "test" {
+  provider = kubernetes-alpha
+
+  api_version = "v1"
+  kind        = "ConfigMap"
+  namespace   = kubernetes_manifest.test.object.metadata.namespace
+  name        = kubernetes_manifest.test.object.metadata.name
+}
+
+data "kubernetes_resource" "missing" {
+  provider = kubernetes-alpha
+
+  api_version = "v1"
+  kind        = "ConfigMap"
+  namespace   = kubernetes_manifest.test.object.metadata.namespace
+  name        = "${var.name}-missing"
+}
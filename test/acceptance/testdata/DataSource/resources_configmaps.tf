@@ -0,0 +1,44 @@
+provider "kubernetes-alpha" {
+}
+
+resource "kubernetes_manifest" "test" {
+  provider = kubernetes-alpha
+  count    = 3
+
+  manifest = {
+    apiVersion = "v1"
+    kind       = "ConfigMap"
+    metadata = {
+      name      = "${var.name}-${count.index}"
+      namespace = var.namespace
+      labels = {
+        selected = count.index < 2 ? "yes" : "no"
+      }
+    }
+    data = {
+      index = tostring(count.index)
+    }
+  }
+}
+
+data "kubernetes_resources" "test" {
+  provider = kubernetes-alpha
+
+  api_version    = "v1"
+  kind           = "ConfigMap"
+  namespace      = var.namespace
+  label_selector = "selected=yes"
+
+  depends_on = [kubernetes_manifest.test]
+}
+
+data "kubernetes_resources" "limited" {
+  provider = kubernetes-alpha
+
+  api_version = "v1"
+  kind        = "ConfigMap"
+  namespace   = var.namespace
+  limit       = 1
+
+  depends_on = [kubernetes_manifest.test]
+}
@@ -0,0 +1,13 @@
+provider "kubernetes-alpha" {
+}
+
+data "kubernetes_schema" "test" {
+  provider = kubernetes-alpha
+
+  api_version = "v1"
+  kind        = "ConfigMap"
+}
+
+output "configmap_type" {
+  value = jsondecode(data.kubernetes_schema.test.type_json)
+}